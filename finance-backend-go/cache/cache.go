@@ -0,0 +1,43 @@
+// Package cache provides a small Get/Set/Del abstraction over the app's
+// hot-path caches (AI category predictions, category lookups, JWT
+// revocation), backed by either an in-memory LRU or Redis depending on
+// CACHE_BACKEND.
+package cache
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Cache is the interface handlers depend on; New selects the concrete
+// backend so callers never need to know which one is active.
+type Cache interface {
+	Get(key string) (string, bool)
+	Set(key string, value string, ttl time.Duration) error
+	Del(key string) error
+}
+
+// New builds a Cache backend from the CACHE_BACKEND env var ("redis" or
+// "lru", default "lru"). Redis connects using REDIS_ADDR (default
+// localhost:6379); a connection failure falls back to the in-memory LRU so a
+// missing Redis instance degrades gracefully instead of crashing the app.
+func New() Cache {
+	backend := os.Getenv("CACHE_BACKEND")
+
+	switch backend {
+	case "redis":
+		addr := os.Getenv("REDIS_ADDR")
+		if addr == "" {
+			addr = "localhost:6379"
+		}
+		redisCache, err := newRedisCache(addr, os.Getenv("REDIS_PASSWORD"))
+		if err != nil {
+			fmt.Printf("cache: failed to connect to Redis at %s, falling back to in-memory LRU: %v\n", addr, err)
+			return newLRUCache(defaultLRUCapacity)
+		}
+		return redisCache
+	default:
+		return newLRUCache(defaultLRUCapacity)
+	}
+}
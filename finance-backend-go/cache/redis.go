@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisCache backs Cache with a shared Redis instance so multiple app
+// instances see the same category-prediction/lookup/revocation cache.
+type redisCache struct {
+	client *redis.Client
+}
+
+func newRedisCache(addr, password string) (*redisCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+
+	return &redisCache{client: client}, nil
+}
+
+func (c *redisCache) Get(key string) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	value, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) || err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+func (c *redisCache) Set(key string, value string, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *redisCache) Del(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return c.client.Del(ctx, key).Err()
+}
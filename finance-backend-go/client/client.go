@@ -0,0 +1,386 @@
+// Package client is a typed Go client for the API described in
+// api/openapi.yaml. It's hand-rolled rather than produced by oapi-codegen
+// (the repo's clients, e.g. ynab.go and fx.go's exchangeRateHostFetcher, are
+// hand-rolled for the same reason: a generated client's surface would track
+// the whole spec, but callers here only ever need a handful of operations).
+// Request/response shapes are the same structs the server binds/returns, so
+// the two can't drift silently.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"finance-backend-go/internal/app"
+)
+
+// Client talks to one instance of the finance backend over HTTP, attaching
+// an access token (if set) to every request the same way the server's
+// JWTMiddleware expects: an `Authorization: Bearer <token>` header.
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	AccessToken string
+}
+
+// New returns a Client pointed at baseURL (e.g. "http://localhost:8080/api/v1").
+func New(baseURL string) *Client {
+	return &Client{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr app.ErrorResponse
+		if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.Message != "" {
+			return fmt.Errorf("API error (%d): %s", resp.StatusCode, apiErr.Message)
+		}
+		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// Login authenticates and, on success, stores the access token on c for
+// subsequent calls.
+func (c *Client) Login(ctx context.Context, username, password string) (*app.AuthResponse, error) {
+	var resp app.AuthResponse
+	if err := c.do(ctx, http.MethodPost, "/auth/login", app.LoginRequest{
+		Username: username,
+		Password: password,
+	}, &resp); err != nil {
+		return nil, err
+	}
+	c.AccessToken = resp.Token
+	return &resp, nil
+}
+
+// Register creates a new user and, on success, stores the access token on c.
+func (c *Client) Register(ctx context.Context, username, password string) (*app.AuthResponse, error) {
+	var resp app.AuthResponse
+	if err := c.do(ctx, http.MethodPost, "/auth/register", app.RegisterRequest{
+		Username: username,
+		Password: password,
+	}, &resp); err != nil {
+		return nil, err
+	}
+	c.AccessToken = resp.Token
+	return &resp, nil
+}
+
+// CreateTransaction posts a new transaction and returns the server's
+// categorized/converted view of it.
+func (c *Client) CreateTransaction(ctx context.Context, req app.CreateTransactionRequest) (*app.TransactionResponse, error) {
+	var resp struct {
+		Transaction app.TransactionResponse `json:"transaction"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/transactions", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Transaction, nil
+}
+
+// GetTransactions lists transactions, optionally filtered by category_id/
+// start_date/end_date (pass "" to leave a filter off).
+func (c *Client) GetTransactions(ctx context.Context, categoryID, startDate, endDate string) ([]app.TransactionResponse, error) {
+	query := ""
+	if categoryID != "" || startDate != "" || endDate != "" {
+		query = "?"
+		if categoryID != "" {
+			query += "category_id=" + categoryID + "&"
+		}
+		if startDate != "" {
+			query += "start_date=" + startDate + "&"
+		}
+		if endDate != "" {
+			query += "end_date=" + endDate
+		}
+	}
+
+	var resp struct {
+		Transactions []app.TransactionResponse `json:"transactions"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/transactions"+query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Transactions, nil
+}
+
+// UpdateTransaction replaces a transaction's editable fields.
+func (c *Client) UpdateTransaction(ctx context.Context, id uint, req app.CreateTransactionRequest) error {
+	return c.do(ctx, http.MethodPut, fmt.Sprintf("/transactions/%d", id), req, nil)
+}
+
+// DeleteTransaction deletes a transaction by ID.
+func (c *Client) DeleteTransaction(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/transactions/%d", id), nil, nil)
+}
+
+// Sync performs one round of the GET /sync delta-sync endpoint, returning
+// the rows changed since sinceKnowledge and the cursor to pass next time.
+func (c *Client) Sync(ctx context.Context, sinceKnowledge uint64) (*app.SyncResponse, error) {
+	var resp app.SyncResponse
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/sync?since_knowledge=%d", sinceKnowledge), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateRecurringTransaction creates a recurring transaction template.
+func (c *Client) CreateRecurringTransaction(ctx context.Context, req app.CreateRecurringTransactionRequest) (*app.RecurringTransaction, error) {
+	var resp struct {
+		Recurring app.RecurringTransaction `json:"recurring"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/recurring", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Recurring, nil
+}
+
+// GetRecurringTransactions lists the caller's recurring transaction
+// templates.
+func (c *Client) GetRecurringTransactions(ctx context.Context) ([]app.RecurringTransaction, error) {
+	var resp struct {
+		Recurring []app.RecurringTransaction `json:"recurring"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/recurring", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Recurring, nil
+}
+
+// GetRecurringTransaction returns a single recurring transaction template by ID.
+func (c *Client) GetRecurringTransaction(ctx context.Context, id uint) (*app.RecurringTransaction, error) {
+	var resp struct {
+		Recurring app.RecurringTransaction `json:"recurring"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/recurring/%d", id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Recurring, nil
+}
+
+// UpdateRecurringTransaction replaces a recurring transaction template's
+// fields and schedule.
+func (c *Client) UpdateRecurringTransaction(ctx context.Context, id uint, req app.CreateRecurringTransactionRequest) (*app.RecurringTransaction, error) {
+	var resp struct {
+		Recurring app.RecurringTransaction `json:"recurring"`
+	}
+	if err := c.do(ctx, http.MethodPut, fmt.Sprintf("/recurring/%d", id), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Recurring, nil
+}
+
+// DeleteRecurringTransaction deletes a recurring transaction template by ID.
+func (c *Client) DeleteRecurringTransaction(ctx context.Context, id uint) error {
+	return c.do(ctx, http.MethodDelete, fmt.Sprintf("/recurring/%d", id), nil, nil)
+}
+
+// RunRecurringNow materializes a recurring transaction's current due
+// occurrence immediately.
+func (c *Client) RunRecurringNow(ctx context.Context, id uint) (*app.TransactionResponse, error) {
+	var resp struct {
+		Transaction app.TransactionResponse `json:"transaction"`
+	}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/recurring/%d/run-now", id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Transaction, nil
+}
+
+// CreateImportProfile saves a reusable CSV column mapping.
+func (c *Client) CreateImportProfile(ctx context.Context, req app.CreateImportProfileRequest) (*app.ImportProfile, error) {
+	var resp struct {
+		Profile app.ImportProfile `json:"profile"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/import/profiles", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.Profile, nil
+}
+
+// GetImportProfiles lists the caller's saved CSV column mappings.
+func (c *Client) GetImportProfiles(ctx context.Context) ([]app.ImportProfile, error) {
+	var resp struct {
+		Profiles []app.ImportProfile `json:"profiles"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/import/profiles", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Profiles, nil
+}
+
+// ImportBatchPreview is the staged preview POST /import returns before a
+// batch is committed.
+type ImportBatchPreview struct {
+	BatchID uint                   `json:"batch_id"`
+	Summary map[string]int         `json:"summary"`
+	Rows    []app.ImportStagingRow `json:"rows"`
+}
+
+// CreateImportBatch uploads a bank export (format is "ofx", "qif", or
+// "csv") for parsing and new/duplicate/conflicting classification. Nothing
+// is written to Transaction until CommitImportBatch is called on the
+// returned batch ID.
+func (c *Client) CreateImportBatch(ctx context.Context, format, account, filename string, file io.Reader) (*ImportBatchPreview, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.WriteField("format", format); err != nil {
+		return nil, fmt.Errorf("failed to encode format field: %w", err)
+	}
+	if account != "" {
+		if err := writer.WriteField("account", account); err != nil {
+			return nil, fmt.Errorf("failed to encode account field: %w", err)
+		}
+	}
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file part: %w", err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return nil, fmt.Errorf("failed to copy file contents: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finalize multipart body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/import", &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if c.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		var apiErr app.ErrorResponse
+		if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, apiErr.Message)
+		}
+		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBody))
+	}
+
+	var preview ImportBatchPreview
+	if err := json.Unmarshal(respBody, &preview); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &preview, nil
+}
+
+// CommitImportBatch commits a staged import batch's accepted rows as real
+// Transactions.
+func (c *Client) CommitImportBatch(ctx context.Context, batchID uint, req app.CommitImportRequest) ([]app.TransactionResponse, error) {
+	var resp struct {
+		Transactions []app.TransactionResponse `json:"transactions"`
+	}
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/import/%d/commit", batchID), req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Transactions, nil
+}
+
+// GetBudgetsForMonth returns the caller's raw envelope budget rows for
+// month ("YYYY-MM").
+func (c *Client) GetBudgetsForMonth(ctx context.Context, month string) ([]app.Budget, error) {
+	var resp struct {
+		Budgets []app.Budget `json:"budgets"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/budgets/"+month, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Budgets, nil
+}
+
+// SetBudgetsForMonth upserts the caller's budget assignment for each
+// category_id in budgets for month ("YYYY-MM").
+func (c *Client) SetBudgetsForMonth(ctx context.Context, month string, budgets []app.SetBudgetRequest) ([]app.Budget, error) {
+	var resp struct {
+		Budgets []app.Budget `json:"budgets"`
+	}
+	req := struct {
+		Budgets []app.SetBudgetRequest `json:"budgets"`
+	}{Budgets: budgets}
+	if err := c.do(ctx, http.MethodPut, "/budgets/"+month, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Budgets, nil
+}
+
+// GetBudgetStatusForMonth returns month's ("YYYY-MM") spend-vs-plan
+// snapshot per budgeted category, including rollover carryover.
+func (c *Client) GetBudgetStatusForMonth(ctx context.Context, month string) ([]app.EnvelopeBudgetStatus, error) {
+	var resp struct {
+		BudgetStatus []app.EnvelopeBudgetStatus `json:"budget_status"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/budgets/"+month+"/status", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.BudgetStatus, nil
+}
+
+// CopyBudgetsFromPreviousMonth seeds month's ("YYYY-MM") budgets from
+// prevMonth, skipping categories already budgeted in month.
+func (c *Client) CopyBudgetsFromPreviousMonth(ctx context.Context, month, prevMonth string) ([]app.Budget, error) {
+	var resp struct {
+		Copied []app.Budget `json:"copied"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/budgets/"+month+"/copy-from/"+prevMonth, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Copied, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"finance-backend-go/internal/app"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// loadConfig resolves app.Config from, in increasing precedence: config.yaml
+// (or the path given by --config), environment variables (unprefixed, e.g.
+// DATABASE_DSN, matching the names the app historically read directly), and
+// command-line flags. This is what replaces the old getEnv-only setup.
+func loadConfig(cmd *cobra.Command) (app.Config, error) {
+	v := viper.New()
+
+	v.SetDefault("port", "8080")
+	v.SetDefault("jwt-secret", "your-secret-key")
+	v.SetDefault("ai-service-url", "http://localhost:5000")
+	v.SetDefault("cache-backend", "lru")
+	v.SetDefault("redis-addr", "localhost:6379")
+	v.SetDefault("db-host", "localhost")
+	v.SetDefault("db-port", "5432")
+	v.SetDefault("db-user", "postgres")
+	v.SetDefault("db-password", "123123")
+	v.SetDefault("db-name", "finance_db")
+	v.SetDefault("db-sslmode", "disable")
+	v.SetDefault("db-timezone", "Asia/Jakarta")
+
+	if configPath, _ := cmd.Flags().GetString("config"); configPath != "" {
+		v.SetConfigFile(configPath)
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath(".")
+	}
+	if err := v.ReadInConfig(); err != nil {
+		if _, notFound := err.(viper.ConfigFileNotFoundError); !notFound {
+			return app.Config{}, err
+		}
+	}
+
+	// Env vars use the historical unprefixed names (DATABASE_DSN, JWT_SECRET,
+	// ...) rather than a SERVER_-style prefix, so existing deployments keep working.
+	envKeys := map[string]string{
+		"port":           "PORT",
+		"database-dsn":   "DATABASE_DSN",
+		"db-host":        "DB_HOST",
+		"db-port":        "DB_PORT",
+		"db-user":        "DB_USER",
+		"db-password":    "DB_PASSWORD",
+		"db-name":        "DB_NAME",
+		"db-sslmode":     "DB_SSLMODE",
+		"db-timezone":    "DB_TIMEZONE",
+		"jwt-secret":     "JWT_SECRET",
+		"ai-service-url": "AI_SERVICE_URL",
+		"cache-backend":  "CACHE_BACKEND",
+		"redis-addr":     "REDIS_ADDR",
+		"redis-password": "REDIS_PASSWORD",
+		"gin-mode":       "GIN_MODE",
+	}
+	for key, env := range envKeys {
+		if err := v.BindEnv(key, env); err != nil {
+			return app.Config{}, err
+		}
+	}
+
+	// cmd.Flags() already includes inherited persistent flags once Cobra has
+	// merged them for the command being executed, so this covers both.
+	if err := v.BindPFlags(cmd.Flags()); err != nil {
+		return app.Config{}, err
+	}
+
+	return app.Config{
+		Port:          v.GetString("port"),
+		DatabaseDSN:   v.GetString("database-dsn"),
+		DBHost:        v.GetString("db-host"),
+		DBPort:        v.GetString("db-port"),
+		DBUser:        v.GetString("db-user"),
+		DBPassword:    v.GetString("db-password"),
+		DBName:        v.GetString("db-name"),
+		DBSSLMode:     v.GetString("db-sslmode"),
+		DBTimezone:    v.GetString("db-timezone"),
+		JWTSecret:     v.GetString("jwt-secret"),
+		AIServiceURL:  v.GetString("ai-service-url"),
+		CacheBackend:  v.GetString("cache-backend"),
+		RedisAddr:     v.GetString("redis-addr"),
+		RedisPassword: v.GetString("redis-password"),
+		GinMode:       v.GetString("gin-mode"),
+	}, nil
+}
+
+// mustConfig loads config for a subcommand, exiting the process via cobra's
+// error path on failure. It also applies the config to the app package's
+// state (jwtSecret, cache env vars, ...) since every subcommand needs that
+// done before touching the database or serving traffic.
+func mustConfig(cmd *cobra.Command) (app.Config, error) {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return app.Config{}, err
+	}
+	app.Configure(cfg)
+	return cfg, nil
+}
@@ -0,0 +1,66 @@
+// Command server is the finance-backend-go entrypoint. It replaces the old
+// single-shot main() with a Cobra command tree (serve, migrate, seed, user,
+// version) so migrations and seeding can run as separate steps from serving
+// traffic, matching how the app is operated in container environments.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// version is overridable at build time via:
+//
+//	go build -ldflags "-X main.version=1.2.3" ./cmd/server
+var version = "dev"
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "server",
+		Short: "finance-backend-go API server",
+		// Runtime failures (bad DSN, existing user, ...) aren't usage errors;
+		// main() prints the returned error once, so don't let Cobra print it
+		// again or dump the flag usage block after it.
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+
+	root.PersistentFlags().String("config", "", "path to config.yaml (default: ./config.yaml if present)")
+	root.PersistentFlags().String("port", "8080", "HTTP port for the serve subcommand")
+	root.PersistentFlags().String("database-dsn", "", "database DSN (postgres://, mysql://, sqlite://, cockroach://)")
+	root.PersistentFlags().String("jwt-secret", "your-secret-key", "secret used to sign access tokens")
+	root.PersistentFlags().String("ai-service-url", "http://localhost:5000", "base URL of the AI categorization service")
+	root.PersistentFlags().String("cache-backend", "lru", "cache backend: lru or redis")
+	root.PersistentFlags().String("redis-addr", "localhost:6379", "redis address when cache-backend=redis")
+	root.PersistentFlags().String("gin-mode", "", "gin mode (release to disable debug logging)")
+
+	root.AddCommand(
+		newServeCmd(),
+		newMigrateCmd(),
+		newSeedCmd(),
+		newUserCmd(),
+		newVersionCmd(),
+	)
+
+	return root
+}
+
+func newVersionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the server version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Println(version)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+
+	"finance-backend-go/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage the database schema",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply the schema (AutoMigrate) to the configured database",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := mustConfig(cmd)
+				if err != nil {
+					return err
+				}
+				if err := app.Connect(cfg); err != nil {
+					return err
+				}
+				defer app.CloseDatabase()
+				return app.RunMigrations()
+			},
+		},
+		&cobra.Command{
+			Use:   "down",
+			Short: "Drop the tables managed by migrate (best-effort, no numbered rollback)",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := mustConfig(cmd)
+				if err != nil {
+					return err
+				}
+				if err := app.Connect(cfg); err != nil {
+					return err
+				}
+				defer app.CloseDatabase()
+				return app.MigrateDown()
+			},
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "Show which managed tables currently exist",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := mustConfig(cmd)
+				if err != nil {
+					return err
+				}
+				if err := app.Connect(cfg); err != nil {
+					return err
+				}
+				defer app.CloseDatabase()
+
+				status, err := app.MigrationStatus()
+				if err != nil {
+					return err
+				}
+				for table, exists := range status {
+					state := "missing"
+					if exists {
+						state = "present"
+					}
+					fmt.Printf("%-24s %s\n", table, state)
+				}
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "backfill-ledger",
+			Short: "Post ledger entries for transactions created before the double-entry ledger",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := mustConfig(cmd)
+				if err != nil {
+					return err
+				}
+				if err := app.Connect(cfg); err != nil {
+					return err
+				}
+				defer app.CloseDatabase()
+				return app.BackfillLedgerPostings()
+			},
+		},
+	)
+
+	return cmd
+}
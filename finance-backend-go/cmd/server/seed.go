@@ -0,0 +1,32 @@
+package main
+
+import (
+	"finance-backend-go/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+func newSeedCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Seed reference data",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "categories",
+		Short: "Create the default system categories, if they don't already exist",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := mustConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if err := app.Connect(cfg); err != nil {
+				return err
+			}
+			defer app.CloseDatabase()
+			return app.CreateDefaultCategories()
+		},
+	})
+
+	return cmd
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"finance-backend-go/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+func newServeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Run the HTTP API server",
+		Long: "Run the HTTP API server. It expects the database to already be " +
+			"migrated (see \"server migrate up\") and does not seed default " +
+			"categories itself (see \"server seed categories\").",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := mustConfig(cmd)
+			if err != nil {
+				return err
+			}
+			return app.Serve(cfg)
+		},
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"finance-backend-go/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+func newUserCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+
+	var admin bool
+	createCmd := &cobra.Command{
+		Use:   "create <username> <password>",
+		Short: "Create a user, optionally as an admin",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := mustConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if err := app.Connect(cfg); err != nil {
+				return err
+			}
+			defer app.CloseDatabase()
+
+			if !admin {
+				return fmt.Errorf("user create currently only supports --admin; use the /api/v1/auth/register endpoint for regular users")
+			}
+
+			user, err := app.CreateAdminUser(args[0], args[1])
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Created admin user %q (id=%d)\n", user.Username, user.ID)
+			return nil
+		},
+	}
+	createCmd.Flags().BoolVar(&admin, "admin", false, "grant the new user admin privileges")
+	cmd.AddCommand(createCmd)
+
+	return cmd
+}
@@ -1,12 +1,18 @@
-package main
+package app
 
 import (
+	"context"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// aiContextTimeout bounds how long a request waits on the AI service before
+// giving up and falling back, independent of the client's own cancellation.
+const aiContextTimeout = 8 * time.Second
+
 // RecategorizeTransaction allows user to manually change category or re-run AI categorization
 func RecategorizeTransaction(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -21,9 +27,9 @@ func RecategorizeTransaction(c *gin.Context) {
 	transactionID := c.Param("id")
 
 	type RecategorizeRequest struct {
-		CategoryID   *uint  `json:"category_id"`           // Manual category selection
-		UseAI        bool   `json:"use_ai"`                // Re-run AI categorization
-		CategoryName string `json:"category_name"`         // Create new category
+		CategoryID   *uint  `json:"category_id"`   // Manual category selection
+		UseAI        bool   `json:"use_ai"`        // Re-run AI categorization
+		CategoryName string `json:"category_name"` // Create new category
 	}
 
 	var req RecategorizeRequest
@@ -52,7 +58,9 @@ func RecategorizeTransaction(c *gin.Context) {
 
 	if req.UseAI {
 		// Re-run AI categorization
-		predictedCategory, aiConfidence, err := GetCategoryWithConfidence(transaction.Description)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), aiContextTimeout)
+		defer cancel()
+		predictedCategory, aiConfidence, err := GetCategoryWithConfidence(ctx, userID.(uint), transaction.Description)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, ErrorResponse{
 				Error:   "ai_error",
@@ -93,9 +101,10 @@ func RecategorizeTransaction(c *gin.Context) {
 
 	} else if req.CategoryName != "" {
 		// Create new category
+		uid := userID.(uint)
 		newCategory := Category{
 			Name:   req.CategoryName,
-			UserID: userID.(uint),
+			UserID: &uid,
 		}
 
 		if err := DB.Create(&newCategory).Error; err != nil {
@@ -121,6 +130,8 @@ func RecategorizeTransaction(c *gin.Context) {
 
 	// Update transaction
 	transaction.CategoryID = &newCategoryID
+	transaction.PredictionMethod = method
+	transaction.AIConfidence = confidence
 	if err := DB.Save(&transaction).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "database_error",
@@ -129,6 +140,8 @@ func RecategorizeTransaction(c *gin.Context) {
 		return
 	}
 
+	recordPrediction(method, categoryName, confidence)
+
 	response := TransactionResponse{
 		ID:               transaction.ID,
 		Description:      transaction.Description,
@@ -151,6 +164,63 @@ func RecategorizeTransaction(c *gin.Context) {
 	})
 }
 
+// batchRecategorizeAI re-runs AI categorization for each of transactions,
+// updating CategoryID/PredictionMethod/AIConfidence in place and returning a
+// TransactionResponse per successfully updated row. It's shared by
+// BatchRecategorize and CommitImportBatch (see import.go), so a bulk import
+// can categorize its newly created transactions the same way a manual batch
+// re-categorization does.
+func batchRecategorizeAI(ctx context.Context, userID uint, transactions []Transaction) ([]TransactionResponse, int, int, error) {
+	descriptions := make([]string, len(transactions))
+	for i, txn := range transactions {
+		descriptions[i] = txn.Description
+	}
+
+	aiResults, err := BatchCategorizeTransactions(ctx, userID, descriptions)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	var updatedTransactions []TransactionResponse
+	var successCount, errorCount int
+	for i, transaction := range transactions {
+		if i >= len(aiResults) {
+			continue
+		}
+		result := aiResults[i]
+		categoryID, categoryName, predMethod, err := findOrCreateCategory(userID, result.PredictedCategory)
+		if err != nil {
+			errorCount++
+			continue
+		}
+
+		transaction.CategoryID = &categoryID
+		transaction.PredictionMethod = predMethod
+		transaction.AIConfidence = result.Confidence
+		if err := DB.Save(&transaction).Error; err != nil {
+			errorCount++
+			continue
+		}
+
+		updatedTransactions = append(updatedTransactions, TransactionResponse{
+			ID:               transaction.ID,
+			Description:      transaction.Description,
+			Amount:           transaction.Amount,
+			TransactionDate:  transaction.TransactionDate,
+			CategoryID:       transaction.CategoryID,
+			CategoryName:     categoryName,
+			UserID:           transaction.UserID,
+			CreatedAt:        transaction.CreatedAt,
+			UpdatedAt:        transaction.UpdatedAt,
+			AIConfidence:     result.Confidence,
+			PredictionMethod: predMethod,
+		})
+		recordPrediction(predMethod, categoryName, result.Confidence)
+		successCount++
+	}
+	return updatedTransactions, successCount, errorCount, nil
+}
+
 // BatchRecategorize allows bulk re-categorization using AI
 func BatchRecategorize(c *gin.Context) {
 	userID, exists := c.Get("user_id")
@@ -207,13 +277,9 @@ func BatchRecategorize(c *gin.Context) {
 	var successCount, errorCount int
 
 	if req.UseAI {
-		// Batch AI categorization
-		descriptions := make([]string, len(transactions))
-		for i, txn := range transactions {
-			descriptions[i] = txn.Description
-		}
-
-		aiResults, err := BatchCategorizeTransactions(descriptions)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), aiContextTimeout)
+		defer cancel()
+		updated, success, failed, err := batchRecategorizeAI(ctx, userID.(uint), transactions)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, ErrorResponse{
 				Error:   "ai_batch_error",
@@ -221,39 +287,9 @@ func BatchRecategorize(c *gin.Context) {
 			})
 			return
 		}
-
-		// Update each transaction
-		for i, transaction := range transactions {
-			if i < len(aiResults) {
-				result := aiResults[i]
-				categoryID, categoryName, predMethod, err := findOrCreateCategory(userID.(uint), result.PredictedCategory)
-				if err != nil {
-					errorCount++
-					continue
-				}
-
-				transaction.CategoryID = &categoryID
-				if err := DB.Save(&transaction).Error; err != nil {
-					errorCount++
-					continue
-				}
-
-				updatedTransactions = append(updatedTransactions, TransactionResponse{
-					ID:               transaction.ID,
-					Description:      transaction.Description,
-					Amount:           transaction.Amount,
-					TransactionDate:  transaction.TransactionDate,
-					CategoryID:       transaction.CategoryID,
-					CategoryName:     categoryName,
-					UserID:           transaction.UserID,
-					CreatedAt:        transaction.CreatedAt,
-					UpdatedAt:        transaction.UpdatedAt,
-					AIConfidence:     result.Confidence,
-					PredictionMethod: predMethod,
-				})
-				successCount++
-			}
-		}
+		updatedTransactions = updated
+		successCount = success
+		errorCount = failed
 
 	} else if req.CategoryID != nil {
 		// Manual batch categorization
@@ -269,7 +305,11 @@ func BatchRecategorize(c *gin.Context) {
 		// Update all transactions
 		result := DB.Model(&Transaction{}).
 			Where("id IN ? AND user_id = ?", req.TransactionIDs, userID).
-			Update("category_id", category.ID)
+			Updates(map[string]interface{}{
+				"category_id":       category.ID,
+				"prediction_method": "manual_batch",
+				"ai_confidence":     1.0,
+			})
 
 		if result.Error != nil {
 			c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -297,6 +337,7 @@ func BatchRecategorize(c *gin.Context) {
 				AIConfidence:     1.0,
 				PredictionMethod: "manual_batch",
 			})
+			recordPrediction("manual_batch", category.Name, 1.0)
 		}
 	} else {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
@@ -314,25 +355,12 @@ func BatchRecategorize(c *gin.Context) {
 	})
 }
 
-// GetAIServiceStatus returns AI service health and info
+// GetAIServiceStatus returns AI service health, info, and circuit breaker state
 func GetAIServiceStatus(c *gin.Context) {
-	// Check AI service health
-	err := CheckAIServiceHealth()
-	isHealthy := err == nil
+	ctx, cancel := context.WithTimeout(c.Request.Context(), aiContextTimeout)
+	defer cancel()
 
-	status := gin.H{
-		"ai_service_healthy": isHealthy,
-	}
-
-	if !isHealthy {
-		status["error"] = err.Error()
-	} else {
-		// Get AI service info
-		info, err := GetAIServiceInfo()
-		if err == nil {
-			status["ai_service_info"] = info
-		}
-	}
+	status, _ := aiServiceStatusSnapshot(ctx)
 
 	c.JSON(http.StatusOK, status)
 }
@@ -352,8 +380,20 @@ func TestAIServiceEndpoint(c *gin.Context) {
 		return
 	}
 
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), aiContextTimeout)
+	defer cancel()
+
 	// Test AI categorization
-	category, confidence, err := GetCategoryWithConfidence(req.Description)
+	category, confidence, err := GetCategoryWithConfidence(ctx, userID.(uint), req.Description)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "ai_error",
@@ -365,8 +405,8 @@ func TestAIServiceEndpoint(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"description":        req.Description,
 		"predicted_category": category,
-		"confidence":        confidence,
-		"timestamp":         "test",
+		"confidence":         confidence,
+		"timestamp":          "test",
 	})
 }
 
@@ -432,22 +472,25 @@ func GetCategoryStats(c *gin.Context) {
 	}
 
 	var methodStats []MethodStat
+	DB.Model(&Transaction{}).
+		Select("COALESCE(NULLIF(prediction_method, ''), 'unknown') as method, COUNT(*) as count").
+		Where("user_id = ?", userID).
+		Group("method").
+		Order("count DESC").
+		Scan(&methodStats)
 
-	// This would require adding a prediction_method field to transactions table
-	// For now, we'll simulate it
 	var totalTransactions int64
 	DB.Model(&Transaction{}).Where("user_id = ?", userID).Count(&totalTransactions)
 
-	// Simulate method distribution (in real implementation, this would come from database)
-	methodStats = append(methodStats, MethodStat{Method: "ai_prediction", Count: totalTransactions * 7 / 10})
-	methodStats = append(methodStats, MethodStat{Method: "manual", Count: totalTransactions * 2 / 10})
-	methodStats = append(methodStats, MethodStat{Method: "default", Count: totalTransactions * 1 / 10})
+	var avgConfidence float64
+	DB.Model(&Transaction{}).Where("user_id = ?", userID).Select("COALESCE(AVG(ai_confidence), 0)").Scan(&avgConfidence)
 
 	c.JSON(http.StatusOK, gin.H{
 		"category_distribution": categoryStats,
 		"prediction_methods":    methodStats,
 		"total_transactions":    totalTransactions,
 		"total_categories":      len(categoryStats),
+		"average_ai_confidence": avgConfidence,
 	})
 }
 
@@ -489,23 +532,33 @@ func SuggestCategories(c *gin.Context) {
 
 	// Convert to response format
 	type CategorySuggestion struct {
-		ID   uint   `json:"id"`
-		Name string `json:"name"`
-		Type string `json:"type"` // "personal" or "system"
+		ID          uint     `json:"id"`
+		Name        string   `json:"name"`
+		Type        string   `json:"type"` // "personal" or "system"
+		PercentUsed *float64 `json:"percent_used,omitempty"`
 	}
 
 	var suggestions []CategorySuggestion
 	for _, cat := range categories {
 		categoryType := "system"
-		if cat.UserID != 0 {
+		if cat.UserID != nil {
 			categoryType = "personal"
 		}
 
-		suggestions = append(suggestions, CategorySuggestion{
+		suggestion := CategorySuggestion{
 			ID:   cat.ID,
 			Name: cat.Name,
 			Type: categoryType,
-		})
+		}
+
+		// Warn the user before they assign a transaction to an already-overspent
+		// personal category.
+		if cat.UserID != nil && cat.Budget > 0 {
+			percentUsed := computeBudgetStatus(cat).PercentUsed
+			suggestion.PercentUsed = &percentUsed
+		}
+
+		suggestions = append(suggestions, suggestion)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -514,70 +567,3 @@ func SuggestCategories(c *gin.Context) {
 		"count":       len(suggestions),
 	})
 }
-
-// ExportTransactions exports transactions to CSV (basic implementation)
-func ExportTransactions(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, ErrorResponse{
-			Error:   "unauthorized",
-			Message: "User ID tidak ditemukan",
-		})
-		return
-	}
-
-	// Get query parameters for filtering
-	startDate := c.Query("start_date")
-	endDate := c.Query("end_date")
-	categoryID := c.Query("category_id")
-
-	// Build query
-	query := DB.Where("user_id = ?", userID).Preload("Category")
-
-	// Apply filters
-	if categoryID != "" {
-		query = query.Where("category_id = ?", categoryID)
-	}
-
-	if startDate != "" {
-		query = query.Where("transaction_date >= ?", startDate)
-	}
-
-	if endDate != "" {
-		query = query.Where("transaction_date <= ?", endDate)
-	}
-
-	// Get transactions
-	var transactions []Transaction
-	if err := query.Order("transaction_date DESC").Find(&transactions).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error:   "database_error",
-			Message: "Gagal mengambil data transaksi",
-		})
-		return
-	}
-
-	// Set response headers for CSV download
-	c.Header("Content-Type", "text/csv")
-	c.Header("Content-Disposition", "attachment; filename=transactions.csv")
-
-	// Write CSV header
-	c.String(http.StatusOK, "ID,Description,Amount,Category,Transaction Date,Created At\n")
-
-	// Write transaction data
-	for _, txn := range transactions {
-		categoryName := "Tidak Berkategori"
-		if txn.Category != nil {
-			categoryName = txn.Category.Name
-		}
-
-		c.String(http.StatusOK, "%d,\"%s\",%.2f,\"%s\",%s,%s\n",
-			txn.ID,
-			txn.Description,
-			txn.Amount,
-			categoryName,
-			txn.TransactionDate.Format("2006-01-02"),
-			txn.CreatedAt.Format("2006-01-02 15:04:05"),
-		)
-	}
-}
\ No newline at end of file
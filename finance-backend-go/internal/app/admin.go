@@ -0,0 +1,169 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole guards a handler behind the caller's JWT "role" claim, which
+// JWTMiddleware copies into the context as "role". Route it after
+// JWTMiddleware.
+func RequireRole(role Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		callerRole, exists := c.Get("role")
+		if !exists || callerRole.(Role) != role {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "forbidden",
+				Message: "Aksi ini memerlukan role " + string(role),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// fallbackCategoryName is where AdminDeleteCategory reassigns orphaned
+// transactions, the same catch-all CreateDefaultCategories seeds and
+// CreateTransaction falls back to when AI categorization fails.
+const fallbackCategoryName = "Lainnya"
+
+// AdminCreateCategoryRequest is the body for POST /admin/categories.
+type AdminCreateCategoryRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// AdminCreateCategory creates a global category (UserID nil) visible to every
+// user, the way CreateDefaultCategories seeds the built-in ones.
+func AdminCreateCategory(c *gin.Context) {
+	var req AdminCreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	category := Category{Name: req.Name}
+	if err := DB.Create(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal membuat kategori global",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Kategori global berhasil dibuat",
+		"category": category,
+	})
+}
+
+// AdminUpdateCategoryRequest is the body for PUT /admin/categories/:id.
+type AdminUpdateCategoryRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// AdminUpdateCategory renames a global category. It only matches categories
+// with a nil UserID; a user's personal category isn't reachable here.
+func AdminUpdateCategory(c *gin.Context) {
+	categoryID := c.Param("id")
+
+	var req AdminUpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var category Category
+	if err := DB.Where("id = ? AND user_id IS NULL", categoryID).First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "category_not_found",
+			Message: "Kategori global tidak ditemukan",
+		})
+		return
+	}
+
+	category.Name = req.Name
+	if err := DB.Save(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengupdate kategori",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Kategori global berhasil diupdate",
+		"category": category,
+	})
+}
+
+// AdminDeleteCategory deletes a global category, reassigning every
+// transaction filed under it (from any user) to the global "Lainnya"
+// category rather than leaving them pointing at a deleted row.
+func AdminDeleteCategory(c *gin.Context) {
+	categoryID := c.Param("id")
+
+	var category Category
+	if err := DB.Where("id = ? AND user_id IS NULL", categoryID).First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "category_not_found",
+			Message: "Kategori global tidak ditemukan",
+		})
+		return
+	}
+
+	if category.Name != fallbackCategoryName {
+		var fallback Category
+		if err := DB.Where("name = ? AND user_id IS NULL", fallbackCategoryName).First(&fallback).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "database_error",
+				Message: "Gagal menemukan kategori fallback '" + fallbackCategoryName + "'",
+			})
+			return
+		}
+
+		if err := DB.Model(&Transaction{}).Where("category_id = ?", category.ID).Update("category_id", fallback.ID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "database_error",
+				Message: "Gagal memindahkan transaksi ke kategori fallback",
+			})
+			return
+		}
+	}
+
+	if err := DB.Delete(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal menghapus kategori",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kategori global berhasil dihapus, transaksi terkait dipindahkan ke '" + fallbackCategoryName + "'",
+	})
+}
+
+// AdminListUsers lists every registered user for the admin console.
+func AdminListUsers(c *gin.Context) {
+	var users []User
+	if err := DB.Order("id ASC").Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengambil data user",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": users,
+	})
+}
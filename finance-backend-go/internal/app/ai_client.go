@@ -0,0 +1,465 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// AI service request/response structures
+type AIRequest struct {
+	Description string `json:"description"`
+}
+
+type AIResponse struct {
+	Description       string  `json:"description"`
+	PredictedCategory string  `json:"predicted_category"`
+	Confidence        float64 `json:"confidence"`
+	PredictionMethod  string  `json:"prediction_method,omitempty"`
+	Timestamp         string  `json:"timestamp,omitempty"`
+}
+
+type AIErrorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// HTTP client for AI service
+var aiClient = &http.Client{
+	Timeout: 10 * time.Second,
+}
+
+// aiServiceURL is populated by Configure from the resolved Viper config.
+var aiServiceURL = "http://localhost:5000"
+
+// Retry policy: 3 attempts beyond the first, with exponential backoff and
+// jitter, applied to network errors and 5xx responses.
+var aiRetryBackoffs = []time.Duration{100 * time.Millisecond, 400 * time.Millisecond, 1600 * time.Millisecond}
+
+const aiFallbackCategory = "Lainnya"
+
+// --- Circuit breaker -------------------------------------------------------
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreaker opens after consecutive failures within a window and keeps
+// rejecting calls (falling back to aiFallbackCategory) until openDuration has
+// passed, at which point it allows a single trial request through.
+type circuitBreaker struct {
+	mu                  sync.Mutex
+	state               breakerState
+	consecutiveFailures int
+	failureThreshold    int
+	openDuration        time.Duration
+	openedAt            time.Time
+}
+
+func newCircuitBreaker(failureThreshold int, openDuration time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		state:            breakerClosed,
+		failureThreshold: failureThreshold,
+		openDuration:     openDuration,
+	}
+}
+
+// Allow reports whether a call should be attempted. When the breaker is open
+// but openDuration has elapsed, it flips to half-open and allows one probe.
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) >= b.openDuration {
+			b.state = breakerHalfOpen
+			return true
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutiveFailures = 0
+	b.state = breakerClosed
+}
+
+func (b *circuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.consecutiveFailures++
+	if b.state == breakerHalfOpen || b.consecutiveFailures >= b.failureThreshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *circuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state.String()
+}
+
+var aiBreaker = newCircuitBreaker(5, 30*time.Second)
+
+// --- Per-user rate limiting --------------------------------------------------
+
+// aiUserLimiters gives every user their own token bucket so one account can't
+// exhaust the shared AI service. 2 requests/sec with a burst of 5 is generous
+// for interactive use while still bounding abuse.
+var aiUserLimiters = struct {
+	mu       sync.Mutex
+	limiters map[uint]*rate.Limiter
+}{limiters: make(map[uint]*rate.Limiter)}
+
+func aiLimiterForUser(userID uint) *rate.Limiter {
+	aiUserLimiters.mu.Lock()
+	defer aiUserLimiters.mu.Unlock()
+
+	limiter, ok := aiUserLimiters.limiters[userID]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(2), 5)
+		aiUserLimiters.limiters[userID] = limiter
+	}
+	return limiter
+}
+
+// --- HTTP helpers with retry -------------------------------------------------
+
+// doWithRetry sends req, retrying on network errors and 5xx responses with
+// exponential backoff and jitter. req.Body, if any, must be re-readable across
+// attempts, so callers pass a bodyFactory instead of a pre-built body.
+func doWithRetry(ctx context.Context, method, endpoint string, bodyFactory func() io.Reader) (*http.Response, error) {
+	var lastErr error
+
+	attempts := len(aiRetryBackoffs) + 1
+	for attempt := 0; attempt < attempts; attempt++ {
+		var body io.Reader
+		if bodyFactory != nil {
+			body = bodyFactory()
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := aiClient.Do(req)
+		if err == nil && resp.StatusCode < 500 {
+			return resp, nil
+		}
+
+		if err == nil {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("AI service returned status %d", resp.StatusCode)
+		} else {
+			lastErr = err
+		}
+
+		if attempt < len(aiRetryBackoffs) {
+			backoff := aiRetryBackoffs[attempt]
+			jitter := time.Duration(rand.Int63n(int64(backoff) / 2))
+			select {
+			case <-time.After(backoff + jitter):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	return nil, lastErr
+}
+
+// aiPredictionCacheTTL controls how long a description -> category mapping is
+// cached; repeated identical descriptions ("Kopi Starbucks") are extremely
+// common in real transaction data.
+const aiPredictionCacheTTL = 24 * time.Hour
+
+type cachedPrediction struct {
+	Category   string  `json:"category"`
+	Confidence float64 `json:"confidence"`
+}
+
+func aiPredictionCacheKey(description string) string {
+	return "ai_prediction:" + strings.ToLower(strings.TrimSpace(description))
+}
+
+// GetCategoryWithConfidence returns both category and confidence score for a
+// single user's request, respecting ctx's deadline, the per-user rate limit,
+// and the circuit breaker. Results are cached per normalized description
+// since the same merchant description repeats constantly across a user's
+// transactions.
+func GetCategoryWithConfidence(ctx context.Context, userID uint, description string) (string, float64, error) {
+	if description == "" {
+		return aiFallbackCategory, 0.0, nil
+	}
+
+	cacheKey := aiPredictionCacheKey(description)
+	if AppCache != nil {
+		if cached, ok := AppCache.Get(cacheKey); ok {
+			var prediction cachedPrediction
+			if err := json.Unmarshal([]byte(cached), &prediction); err == nil {
+				return prediction.Category, prediction.Confidence, nil
+			}
+		}
+	}
+
+	if !aiLimiterForUser(userID).Allow() {
+		return aiFallbackCategory, 0.0, fmt.Errorf("rate limit exceeded for user %d", userID)
+	}
+
+	if !aiBreaker.Allow() {
+		return aiFallbackCategory, 0.0, nil
+	}
+
+	endpoint := aiServiceURL + "/categorize"
+
+	requestData := AIRequest{Description: description}
+	jsonData, err := json.Marshal(requestData)
+	if err != nil {
+		return aiFallbackCategory, 0.0, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := doWithRetry(ctx, "POST", endpoint, func() io.Reader { return bytes.NewReader(jsonData) })
+	if err != nil {
+		aiBreaker.RecordFailure()
+		return aiFallbackCategory, 0.0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		aiBreaker.RecordFailure()
+		return aiFallbackCategory, 0.0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		aiBreaker.RecordFailure()
+		var errorResp AIErrorResponse
+		if err := json.Unmarshal(body, &errorResp); err == nil {
+			return aiFallbackCategory, 0.0, fmt.Errorf("AI service error (%d): %s", resp.StatusCode, errorResp.Message)
+		}
+		return aiFallbackCategory, 0.0, fmt.Errorf("AI service error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var aiResp AIResponse
+	if err := json.Unmarshal(body, &aiResp); err != nil {
+		aiBreaker.RecordFailure()
+		return aiFallbackCategory, 0.0, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	aiBreaker.RecordSuccess()
+
+	if aiResp.PredictedCategory == "" {
+		return aiFallbackCategory, 0.0, nil
+	}
+
+	if AppCache != nil {
+		if encoded, err := json.Marshal(cachedPrediction{Category: aiResp.PredictedCategory, Confidence: aiResp.Confidence}); err == nil {
+			AppCache.Set(cacheKey, string(encoded), aiPredictionCacheTTL)
+		}
+	}
+
+	return aiResp.PredictedCategory, aiResp.Confidence, nil
+}
+
+// GetCategoryFromAI calls the AI service to predict transaction category
+func GetCategoryFromAI(ctx context.Context, userID uint, description string) (string, error) {
+	category, _, err := GetCategoryWithConfidence(ctx, userID, description)
+	return category, err
+}
+
+// BatchCategorizeTransactions categorizes multiple transactions at once
+func BatchCategorizeTransactions(ctx context.Context, userID uint, descriptions []string) ([]AIResponse, error) {
+	if len(descriptions) == 0 {
+		return []AIResponse{}, nil
+	}
+
+	if !aiLimiterForUser(userID).AllowN(time.Now(), len(descriptions)) {
+		return nil, fmt.Errorf("rate limit exceeded for user %d", userID)
+	}
+
+	if !aiBreaker.Allow() {
+		fallback := make([]AIResponse, len(descriptions))
+		for i, desc := range descriptions {
+			fallback[i] = AIResponse{Description: desc, PredictedCategory: aiFallbackCategory, PredictionMethod: "circuit_open_fallback"}
+		}
+		return fallback, nil
+	}
+
+	endpoint := aiServiceURL + "/categorize/batch"
+
+	type BatchRequest struct {
+		Transactions []AIRequest `json:"transactions"`
+	}
+
+	var transactions []AIRequest
+	for _, desc := range descriptions {
+		transactions = append(transactions, AIRequest{Description: desc})
+	}
+
+	jsonData, err := json.Marshal(BatchRequest{Transactions: transactions})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal batch request: %w", err)
+	}
+
+	resp, err := doWithRetry(ctx, "POST", endpoint, func() io.Reader { return bytes.NewReader(jsonData) })
+	if err != nil {
+		aiBreaker.RecordFailure()
+		return nil, fmt.Errorf("failed to send batch request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		aiBreaker.RecordFailure()
+		return nil, fmt.Errorf("failed to read batch response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		aiBreaker.RecordFailure()
+		var errorResp AIErrorResponse
+		if err := json.Unmarshal(body, &errorResp); err == nil {
+			return nil, fmt.Errorf("AI service batch error (%d): %s", resp.StatusCode, errorResp.Message)
+		}
+		return nil, fmt.Errorf("AI service batch error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	type BatchResponse struct {
+		Results        []AIResponse `json:"results"`
+		TotalProcessed int          `json:"total_processed"`
+		Timestamp      string       `json:"timestamp"`
+	}
+
+	var batchResp BatchResponse
+	if err := json.Unmarshal(body, &batchResp); err != nil {
+		aiBreaker.RecordFailure()
+		return nil, fmt.Errorf("failed to parse batch response: %w", err)
+	}
+
+	aiBreaker.RecordSuccess()
+	return batchResp.Results, nil
+}
+
+// CheckAIServiceHealth checks if the AI service is healthy
+func CheckAIServiceHealth(ctx context.Context) error {
+	endpoint := aiServiceURL + "/health"
+
+	resp, err := doWithRetry(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("AI service unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("AI service unhealthy: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// GetAIServiceInfo returns information about the AI service
+func GetAIServiceInfo(ctx context.Context) (map[string]interface{}, error) {
+	endpoint := aiServiceURL + "/health"
+
+	resp, err := doWithRetry(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI service info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read info response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("AI service info error: status %d", resp.StatusCode)
+	}
+
+	var info map[string]interface{}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse info response: %w", err)
+	}
+
+	return info, nil
+}
+
+// aiServiceStatusSnapshot reports the breaker state alongside health/info, so
+// operators can see why predictions might be falling back to aiFallbackCategory.
+// Exposed over HTTP via the GetAIServiceStatus handler.
+func aiServiceStatusSnapshot(ctx context.Context) (map[string]interface{}, error) {
+	status := map[string]interface{}{
+		"breaker_state": aiBreaker.State(),
+	}
+
+	if err := CheckAIServiceHealth(ctx); err != nil {
+		status["healthy"] = false
+		status["error"] = err.Error()
+		return status, nil
+	}
+	status["healthy"] = true
+
+	info, err := GetAIServiceInfo(ctx)
+	if err == nil {
+		status["info"] = info
+	}
+
+	return status, nil
+}
+
+// TestAIService tests the AI service with a sample transaction
+func TestAIService() error {
+	testDescription := "Beli nasi ayam di warteg"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	category, confidence, err := GetCategoryWithConfidence(ctx, 0, testDescription)
+	if err != nil {
+		return fmt.Errorf("AI service test failed: %w", err)
+	}
+
+	if category == "" {
+		return fmt.Errorf("AI service returned empty category")
+	}
+
+	fmt.Printf("AI Service Test - Description: '%s' -> Category: '%s' (Confidence: %.3f)\n",
+		testDescription, category, confidence)
+
+	return nil
+}
@@ -0,0 +1,758 @@
+package app
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"finance-backend-go/cache"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+)
+
+// AppCache backs AI category-prediction/lookup caching and the JWT
+// revocation fast path; selected by CACHE_BACKEND (see cache.New).
+var AppCache cache.Cache
+
+// Serve wires up the cache, background jobs, and HTTP router and blocks
+// serving traffic on cfg.Port. It assumes the database has already been
+// connected and migrated (see Connect and RunMigrations) — the serve
+// subcommand no longer migrates or seeds on every boot so that those can run
+// as a separate init step in container environments.
+func Serve(cfg Config) error {
+	// Initialize cache (in-memory LRU by default, Redis if CACHE_BACKEND=redis)
+	AppCache = cache.New()
+
+	// Test AI service connection on startup
+	log.Println("🔍 Testing AI service connection...")
+	if err := TestAIService(); err != nil {
+		log.Printf("⚠️ AI service test failed: %v", err)
+		log.Println("📝 Application will continue with basic functionality")
+	} else {
+		log.Println("✅ AI service connection successful")
+	}
+
+	if err := Connect(cfg); err != nil {
+		return err
+	}
+	defer CloseDatabase()
+
+	// Periodically refresh YNAB accounts that opted into auto-sync
+	go startYNABAutoSyncScheduler(15 * time.Minute)
+
+	// Keep finance_ai_service_up in sync with the AI service's real health
+	go startAIServiceHealthGauge(30 * time.Second)
+
+	// Clean up expired Idempotency-Key records
+	go pruneExpiredIdempotencyRecords(1 * time.Hour)
+
+	// Nightly check for categories crossing their 80%/100% budget thresholds
+	go startNightlyBudgetAlertJob(24 * time.Hour)
+
+	// Daily pull of FX rates for every currency pair in use (see fx.go)
+	go startFXRefreshScheduler(24 * time.Hour)
+
+	// Materialize due recurring transactions once a minute, stopping cleanly
+	// when Serve returns (e.g. router.Run erroring out).
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go startRecurringScheduler(schedulerCtx, 1*time.Minute)
+
+	// Initialize Gin router
+	router := SetupRouter()
+
+	// Setup routes
+	SetupRoutes(router)
+
+	log.Printf("🚀 Server starting on port %s", cfg.Port)
+	log.Printf("📚 API Documentation available at: http://localhost:%s/health", cfg.Port)
+	log.Printf("🤖 AI Service URL: %s", cfg.AIServiceURL)
+
+	return router.Run(":" + cfg.Port)
+}
+
+func SetupRouter() *gin.Engine {
+	// Set Gin mode based on environment
+	if os.Getenv("GIN_MODE") == "release" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	router := gin.Default()
+
+	// CORS configuration
+	config := cors.DefaultConfig()
+	config.AllowAllOrigins = true
+	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
+	config.AllowHeaders = []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "accept", "origin", "Cache-Control", "X-Requested-With"}
+	config.ExposeHeaders = []string{"Content-Length"}
+	config.AllowCredentials = true
+
+	router.Use(cors.New(config))
+
+	// Recovery middleware
+	router.Use(gin.Recovery())
+
+	// Request logging middleware (optional)
+	router.Use(gin.Logger())
+
+	return router
+}
+
+func SetupRoutes(router *gin.Engine) {
+	// Health check endpoint
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status":              "healthy",
+			"service":             "finance-backend-go",
+			"version":             "1.0.0",
+			"recurring_scheduler": recurringSchedulerHealth(),
+		})
+	})
+
+	// Prometheus metrics endpoint
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// API v1 routes
+	v1 := router.Group("/api/v1")
+	{
+		// Public routes (no authentication required)
+		auth := v1.Group("/auth")
+		{
+			auth.POST("/register", RegisterUser)
+			auth.POST("/login", LoginUser)
+			auth.POST("/refresh", RefreshToken)
+		}
+
+		// Protected routes (JWT authentication required)
+		protected := v1.Group("/")
+		protected.Use(JWTMiddleware())
+		{
+			// User profile routes
+			protected.GET("/profile", GetUserProfile)
+
+			// Session management routes
+			protected.POST("/auth/logout", LogoutUser)
+			protected.GET("/auth/sessions", ListSessions)
+			protected.DELETE("/auth/sessions/:id", RequireReauth(reauthMaxAge), RevokeSession)
+
+			// Step-up auth: proves the caller knows the password right now, so
+			// RequireReauth-guarded routes below will accept the resulting token.
+			protected.POST("/auth/reauthenticate", Reauthenticate)
+			protected.POST("/auth/password", RequireReauth(reauthMaxAge), ChangePassword)
+
+			// Category routes
+			protected.GET("/categories", GetCategories)
+			protected.POST("/categories", CreateCategory)
+			protected.DELETE("/categories/:id", RequireReauth(reauthMaxAge), DeleteCategory)
+
+			// Transaction routes
+			protected.POST("/transactions", CreateTransaction)
+			protected.GET("/transactions", GetTransactions)
+			protected.DELETE("/transactions/bulk", RequireReauth(reauthMaxAge), BulkDeleteTransactions)
+			protected.GET("/transactions/:id", GetTransactionByID)
+			protected.PUT("/transactions/:id", UpdateTransaction)
+			protected.DELETE("/transactions/:id", DeleteTransaction)
+
+			// AI Integration routes
+			protected.PUT("/transactions/:id/recategorize", IdempotencyMiddleware(), RecategorizeTransaction)
+			protected.POST("/transactions/batch-recategorize", IdempotencyMiddleware(), BatchRecategorize)
+			protected.POST("/ai/test", TestAIServiceEndpoint)
+			protected.GET("/ai/status", GetAIServiceStatus)
+
+			// Enhanced category routes
+			protected.GET("/categories/suggest", SuggestCategories)
+			protected.GET("/categories/stats", GetCategoryStats)
+			protected.PUT("/categories/:id/budget", SetCategoryBudget)
+			protected.GET("/categories/budget-status", GetBudgetStatus)
+			protected.GET("/categories/budget-alerts", GetBudgetAlerts)
+
+			// Export routes
+			protected.GET("/export/transactions", ExportTransactions)
+
+			// YNAB integration routes
+			protected.POST("/ynab/connect", ConnectYNAB)
+			protected.POST("/ynab/sync", SyncYNAB)
+			protected.DELETE("/ynab/disconnect", DisconnectYNAB)
+
+			// Statistics routes
+			protected.GET("/stats/summary", GetTransactionSummary)
+			protected.GET("/stats/monthly", GetMonthlyStats)
+
+			// Ledger routes: double-entry postings, bypassing AI categorization
+			protected.POST("/ledger/transactions", CreateLedgerTransaction)
+
+			// Delta sync for offline/mobile clients (see sync.go)
+			protected.GET("/sync", GetSync)
+
+			// Recurring transaction routes: templates materialized on a
+			// schedule by startRecurringScheduler (see recurring.go)
+			protected.POST("/recurring", CreateRecurringTransaction)
+			protected.GET("/recurring", GetRecurringTransactions)
+			protected.GET("/recurring/:id", GetRecurringTransactionByID)
+			protected.PUT("/recurring/:id", UpdateRecurringTransaction)
+			protected.DELETE("/recurring/:id", DeleteRecurringTransaction)
+			protected.POST("/recurring/:id/run-now", RunRecurringNow)
+
+			// Bulk bank-export import routes (see import.go)
+			protected.POST("/import/profiles", CreateImportProfile)
+			protected.GET("/import/profiles", GetImportProfiles)
+			protected.POST("/import", CreateImportBatch)
+			protected.POST("/import/:batch_id/commit", CommitImportBatch)
+
+			// Envelope budgeting routes: per-month, per-category assignments
+			// with rollover math (see envelope.go)
+			protected.GET("/budgets/:month", GetBudgetsForMonth)
+			protected.PUT("/budgets/:month", SetBudgetsForMonth)
+			protected.GET("/budgets/:month/status", GetBudgetStatusForMonth)
+			protected.POST("/budgets/:month/copy-from/:prev_month", CopyBudgetsFromPreviousMonth)
+		}
+
+		// Admin-only routes (JWT authentication + admin role required)
+		admin := v1.Group("/admin")
+		admin.Use(JWTMiddleware(), RequireRole(RoleAdmin))
+		{
+			admin.GET("/users", AdminListUsers)
+			admin.POST("/categories", AdminCreateCategory)
+			admin.PUT("/categories/:id", AdminUpdateCategory)
+			admin.DELETE("/categories/:id", AdminDeleteCategory)
+		}
+
+		// FX rate management (admin only): manual entries supplementing
+		// RefreshExchangeRates' automatic daily pull.
+		fx := v1.Group("/fx")
+		fx.Use(JWTMiddleware(), RequireRole(RoleAdmin))
+		{
+			fx.POST("/rates", AdminSetExchangeRate)
+		}
+	}
+}
+
+// Additional handlers that might be needed
+func GetUserProfile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+
+	var user User
+	if err := DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "user_not_found",
+			Message: "User tidak ditemukan",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user": gin.H{
+			"id":       user.ID,
+			"username": user.Username,
+		},
+	})
+}
+
+func GetCategories(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var categories []Category
+	// Get user categories and system categories (user_id IS NULL)
+	if err := DB.Where("user_id = ? OR user_id IS NULL", userID).Find(&categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengambil data kategori",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"categories": categories,
+	})
+}
+
+func CreateCategory(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required,max=100"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uid := userID.(uint)
+
+	category := Category{
+		Name:   req.Name,
+		UserID: &uid,
+	}
+
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		knowledge, err := nextServerKnowledge(tx, uid)
+		if err != nil {
+			return err
+		}
+		category.ServerKnowledge = knowledge
+		return tx.Create(&category).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal membuat kategori",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Kategori berhasil dibuat",
+		"category": category,
+	})
+}
+
+// BulkDeleteTransactionsRequest is the body for DELETE /transactions/bulk,
+// registered behind RequireReauth since it can wipe a user's history in one
+// call.
+type BulkDeleteTransactionsRequest struct {
+	TransactionIDs []uint `json:"transaction_ids" binding:"required"`
+}
+
+// BulkDeleteTransactions deletes every transaction ID owned by the caller,
+// silently skipping IDs that don't exist or belong to someone else.
+func BulkDeleteTransactions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req BulkDeleteTransactionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if len(req.TransactionIDs) == 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "empty_request",
+			Message: "Tidak ada transaksi yang dipilih",
+		})
+		return
+	}
+
+	var deletedCount int64
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		// The Transaction rows are only soft-deleted; their Postings have no
+		// DeletedAt of their own, so they must be removed here too or
+		// fetchPostingRows would keep counting them forever.
+		if err := tx.Where("transaction_id IN (SELECT id FROM transactions WHERE id IN ? AND user_id = ?)", req.TransactionIDs, userID).
+			Delete(&Posting{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id IN ? AND user_id = ?", req.TransactionIDs, userID).Delete(&Transaction{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deletedCount = result.RowsAffected
+		return nil
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal menghapus transaksi",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Transaksi berhasil dihapus",
+		"deleted_count": deletedCount,
+	})
+}
+
+// DeleteCategory deletes a category the caller owns along with every
+// transaction filed under it. It's registered behind RequireReauth since the
+// cascade is irreversible.
+func DeleteCategory(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	categoryID := c.Param("id")
+
+	var category Category
+	if err := DB.Where("id = ? AND user_id = ?", categoryID, userID).First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "category_not_found",
+			Message: "Kategori tidak ditemukan",
+		})
+		return
+	}
+
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		// The Transaction rows are only soft-deleted; their Postings have no
+		// DeletedAt of their own, so they must be removed here too or
+		// fetchPostingRows would keep counting them forever.
+		if err := tx.Where("transaction_id IN (SELECT id FROM transactions WHERE category_id = ? AND user_id = ?)", category.ID, userID).
+			Delete(&Posting{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("category_id = ? AND user_id = ?", category.ID, userID).Delete(&Transaction{}).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal menghapus transaksi pada kategori ini",
+		})
+		return
+	}
+
+	if err := DB.Delete(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal menghapus kategori",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Kategori dan seluruh transaksinya berhasil dihapus",
+	})
+}
+
+func GetTransactionByID(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	transactionID := c.Param("id")
+
+	var transaction Transaction
+	if err := DB.Preload("Category").Where("id = ? AND user_id = ?", transactionID, userID).First(&transaction).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "transaction_not_found",
+			Message: "Transaksi tidak ditemukan",
+		})
+		return
+	}
+
+	var categoryName string
+	if transaction.Category != nil {
+		categoryName = transaction.Category.Name
+	}
+
+	response := TransactionResponse{
+		ID:               transaction.ID,
+		Description:      transaction.Description,
+		Amount:           transaction.Amount,
+		TransactionDate:  transaction.TransactionDate,
+		CategoryID:       transaction.CategoryID,
+		CategoryName:     categoryName,
+		UserID:           transaction.UserID,
+		CreatedAt:        transaction.CreatedAt,
+		UpdatedAt:        transaction.UpdatedAt,
+		AIConfidence:     transaction.AIConfidence,
+		PredictionMethod: transaction.PredictionMethod,
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transaction": response,
+	})
+}
+
+func UpdateTransaction(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	transactionID := c.Param("id")
+
+	var req CreateTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var transaction Transaction
+	if err := DB.Where("id = ? AND user_id = ?", transactionID, userID).First(&transaction).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "transaction_not_found",
+			Message: "Transaksi tidak ditemukan",
+		})
+		return
+	}
+
+	// Update fields
+	transaction.Description = req.Description
+	transaction.Amount = req.Amount
+	if req.TransactionDate != nil {
+		transaction.TransactionDate = *req.TransactionDate
+	}
+	if req.Currency != "" {
+		transaction.Currency = req.Currency
+	}
+
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		knowledge, err := nextServerKnowledge(tx, userID.(uint))
+		if err != nil {
+			return err
+		}
+		transaction.ServerKnowledge = knowledge
+		if err := tx.Save(&transaction).Error; err != nil {
+			return err
+		}
+
+		// Amount/Currency may have just changed, so the old balanced legs no
+		// longer reflect this transaction; drop them and let
+		// postTransactionEntries (a no-op when postings already exist) lay
+		// down fresh ones against the updated values. Skip this for a
+		// ledger-originated entry with a custom multi-leg structure (see
+		// CreateLedgerTransaction) — rewriting it into a generic 2-leg shape
+		// would silently destroy legs this endpoint knows nothing about.
+		simple, err := hasSimpleLedgerPostings(tx, transaction.ID)
+		if err != nil {
+			return err
+		}
+		if !simple {
+			return nil
+		}
+
+		if err := tx.Where("transaction_id = ?", transaction.ID).Delete(&Posting{}).Error; err != nil {
+			return err
+		}
+		return postTransactionEntries(tx, transaction)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengupdate transaksi",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Transaksi berhasil diupdate",
+	})
+}
+
+func DeleteTransaction(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	uid := userID.(uint)
+	transactionID := c.Param("id")
+
+	var transaction Transaction
+	if err := DB.Where("id = ? AND user_id = ?", transactionID, uid).First(&transaction).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "transaction_not_found",
+			Message: "Transaksi tidak ditemukan",
+		})
+		return
+	}
+
+	// A hard delete leaves no row for GetSync to read a ServerKnowledge off
+	// of, so a tombstone is written in the same transaction to stand in for it.
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		knowledge, err := nextServerKnowledge(tx, uid)
+		if err != nil {
+			return err
+		}
+		if err := recordTombstone(tx, uid, SyncEntityTransaction, transaction.ID, knowledge); err != nil {
+			return err
+		}
+		// The Transaction row is only soft-deleted; its Postings have no
+		// DeletedAt of their own, so they must be removed here too or
+		// fetchPostingRows would keep counting them forever.
+		if err := tx.Where("transaction_id = ?", transaction.ID).Delete(&Posting{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&transaction).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal menghapus transaksi",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Transaksi berhasil dihapus",
+	})
+}
+
+// postingRow is one posting joined with its Account's type and its parent
+// Transaction's date/currency context, the shape GetTransactionSummary and
+// GetMonthlyStats both aggregate over.
+type postingRow struct {
+	Amount          float64
+	Currency        string
+	AccountType     AccountType
+	TransactionDate time.Time
+}
+
+// fetchPostingRows loads userID's postings joined through Accounts and
+// Transactions, optionally restricted to transactions on or after since.
+func fetchPostingRows(userID interface{}, since *time.Time) ([]postingRow, error) {
+	query := DB.Table("postings").
+		Select("postings.amount AS amount, postings.currency AS currency, accounts.type AS account_type, transactions.transaction_date AS transaction_date").
+		Joins("JOIN accounts ON accounts.id = postings.account_id").
+		Joins("JOIN transactions ON transactions.id = postings.transaction_id").
+		Where("transactions.user_id = ?", userID)
+	if since != nil {
+		query = query.Where("transactions.transaction_date >= ?", *since)
+	}
+
+	var rows []postingRow
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// currencyTotals is one currency's native (unconverted) income/expense sums.
+type currencyTotals struct {
+	Income  float64 `json:"income"`
+	Expense float64 `json:"expense"`
+}
+
+// GetTransactionSummary aggregates from Postings grouped by AccountType
+// rather than the sign of Transaction.Amount, so it reflects the
+// double-entry ledger (see ledger.go) instead of the legacy single-amount
+// model. Each posting is converted to the user's BaseCurrency using the FX
+// rate closest to its transaction's date (see lookupExchangeRate) so
+// total_income/total_expense/balance are comparable across currencies; the
+// native, unconverted per-currency breakdown is also returned. Transactions
+// with no postings yet (pre-ledger rows that haven't been through
+// `migrate backfill-ledger`) simply don't contribute.
+func GetTransactionSummary(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	baseCurrency := userBaseCurrency(userID.(uint))
+
+	var transactionCount int64
+	DB.Model(&Transaction{}).Where("user_id = ?", userID).Count(&transactionCount)
+
+	rows, err := fetchPostingRows(userID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengambil ringkasan transaksi",
+		})
+		return
+	}
+
+	var totalIncome, totalExpense, balance float64
+	nativeBreakdown := make(map[string]*currencyTotals)
+
+	for _, row := range rows {
+		native, ok := nativeBreakdown[row.Currency]
+		if !ok {
+			native = &currencyTotals{}
+			nativeBreakdown[row.Currency] = native
+		}
+
+		converted, _ := convertToBaseCurrency(row.Amount, row.Currency, baseCurrency, row.TransactionDate)
+
+		switch row.AccountType {
+		case AccountTypeIncome:
+			// Income postings are credits (negative); negate for a positive total.
+			native.Income += -row.Amount
+			totalIncome += -converted
+		case AccountTypeExpense:
+			native.Expense += row.Amount
+			totalExpense += converted
+		case AccountTypeAsset:
+			balance += converted
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"summary": gin.H{
+			"total_income":      totalIncome,
+			"total_expense":     totalExpense,
+			"balance":           balance,
+			"transaction_count": transactionCount,
+			"base_currency":     baseCurrency,
+			"native_breakdown":  nativeBreakdown,
+		},
+	})
+}
+
+// GetMonthlyStats aggregates from Postings joined through Accounts by
+// AccountType, grouped by month, rather than the sign of Transaction.Amount
+// (see GetTransactionSummary). Each posting is converted to the user's
+// BaseCurrency using the rate closest to its transaction date; native,
+// unconverted per-currency totals are also reported per month.
+func GetMonthlyStats(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	baseCurrency := userBaseCurrency(userID.(uint))
+
+	type MonthlyData struct {
+		Month   string  `json:"month"`
+		Income  float64 `json:"income"`
+		Expense float64 `json:"expense"`
+		Balance float64 `json:"balance"`
+		// BudgetedExpense is the sum of that month's envelope Budget
+		// assignments (see envelope.go), so callers can compare planned vs.
+		// actual spend alongside Expense.
+		BudgetedExpense float64                    `json:"budgeted_expense"`
+		NativeBreakdown map[string]*currencyTotals `json:"native_breakdown"`
+	}
+
+	since := time.Now().AddDate(0, -6, 0)
+	rows, err := fetchPostingRows(userID, &since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengambil statistik bulanan",
+		})
+		return
+	}
+
+	byMonth := make(map[string]*MonthlyData)
+	for _, row := range rows {
+		month := row.TransactionDate.Format("2006-01")
+		data, ok := byMonth[month]
+		if !ok {
+			data = &MonthlyData{Month: month, NativeBreakdown: make(map[string]*currencyTotals)}
+			byMonth[month] = data
+		}
+
+		native, ok := data.NativeBreakdown[row.Currency]
+		if !ok {
+			native = &currencyTotals{}
+			data.NativeBreakdown[row.Currency] = native
+		}
+
+		converted, _ := convertToBaseCurrency(row.Amount, row.Currency, baseCurrency, row.TransactionDate)
+
+		switch row.AccountType {
+		case AccountTypeIncome:
+			native.Income += -row.Amount
+			data.Income += -converted
+		case AccountTypeExpense:
+			native.Expense += row.Amount
+			data.Expense += converted
+		}
+	}
+
+	months := make([]string, 0, len(byMonth))
+	for month := range byMonth {
+		months = append(months, month)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(months)))
+
+	monthlyStats := make([]*MonthlyData, 0, len(months))
+	for _, month := range months {
+		data := byMonth[month]
+		data.Balance = data.Income - data.Expense
+		DB.Model(&Budget{}).Where("user_id = ? AND month = ?", userID, month).
+			Select("COALESCE(SUM(assigned_amount), 0)").Scan(&data.BudgetedExpense)
+		monthlyStats = append(monthlyStats, data)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"monthly_stats": monthlyStats,
+		"base_currency": baseCurrency,
+	})
+}
@@ -0,0 +1,257 @@
+package app
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var validBudgetPeriods = map[string]bool{"weekly": true, "monthly": true, "yearly": true}
+
+// budgetPeriodBounds returns the [start, end) window of the given period that
+// contains now. Monthly/yearly follow calendar months/years; weekly starts on
+// Monday.
+func budgetPeriodBounds(period string, now time.Time) (time.Time, time.Time) {
+	switch period {
+	case "weekly":
+		weekday := int(now.Weekday())
+		if weekday == 0 {
+			weekday = 7 // ISO: Sunday is day 7
+		}
+		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(weekday - 1))
+		return start, start.AddDate(0, 0, 7)
+	case "yearly":
+		start := time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(1, 0, 0)
+	default: // monthly
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+}
+
+// BudgetStatus is the computed spend-vs-budget snapshot for one category.
+type BudgetStatus struct {
+	CategoryID           uint      `json:"category_id"`
+	CategoryName         string    `json:"category"`
+	Spent                float64   `json:"spent"`
+	Budget               float64   `json:"budget"`
+	Remaining            float64   `json:"remaining"`
+	PercentUsed          float64   `json:"percent_used"`
+	ProjectedEndOfPeriod float64   `json:"projected_end_of_period"`
+	PeriodStart          time.Time `json:"period_start"`
+	PeriodEnd            time.Time `json:"period_end"`
+}
+
+// computeBudgetStatus computes the current-period spend for a single budgeted
+// category using a simple linear projection from days elapsed.
+func computeBudgetStatus(category Category) BudgetStatus {
+	now := time.Now()
+	period := category.BudgetPeriod
+	if !validBudgetPeriods[period] {
+		period = "monthly"
+	}
+	start, end := budgetPeriodBounds(period, now)
+
+	var spent float64
+	DB.Model(&Transaction{}).
+		Where("category_id = ? AND amount < 0 AND transaction_date >= ? AND transaction_date < ?", category.ID, start, end).
+		Select("COALESCE(SUM(ABS(amount)), 0)").
+		Scan(&spent)
+
+	percentUsed := 0.0
+	if category.Budget > 0 {
+		percentUsed = spent / category.Budget * 100
+	}
+
+	daysElapsed := now.Sub(start).Hours() / 24
+	if daysElapsed < 1 {
+		daysElapsed = 1
+	}
+	daysTotal := end.Sub(start).Hours() / 24
+	projected := spent / daysElapsed * daysTotal
+
+	return BudgetStatus{
+		CategoryID:           category.ID,
+		CategoryName:         category.Name,
+		Spent:                spent,
+		Budget:               category.Budget,
+		Remaining:            category.Budget - spent,
+		PercentUsed:          percentUsed,
+		ProjectedEndOfPeriod: projected,
+		PeriodStart:          start,
+		PeriodEnd:            end,
+	}
+}
+
+// SetCategoryBudget sets or clears the monthly/weekly/yearly budget on a
+// category owned by the caller.
+func SetCategoryBudget(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+
+	categoryID := c.Param("id")
+
+	var req struct {
+		Budget       float64 `json:"budget" binding:"required,min=0"`
+		BudgetPeriod string  `json:"budget_period" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if !validBudgetPeriods[req.BudgetPeriod] {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_budget_period",
+			Message: "budget_period harus salah satu dari: weekly, monthly, yearly",
+		})
+		return
+	}
+
+	var category Category
+	if err := DB.Where("id = ? AND user_id = ?", categoryID, userID).First(&category).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "category_not_found",
+			Message: "Kategori tidak ditemukan",
+		})
+		return
+	}
+
+	category.Budget = req.Budget
+	category.BudgetPeriod = req.BudgetPeriod
+	if err := DB.Save(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal menyimpan budget kategori",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Budget kategori berhasil disimpan",
+		"category": category,
+	})
+}
+
+// GetBudgetStatus returns spent-vs-budget for every category the user has
+// budgeted, for the category's active period.
+func GetBudgetStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+
+	var categories []Category
+	if err := DB.Where("user_id = ? AND budget > 0", userID).Find(&categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengambil data kategori",
+		})
+		return
+	}
+
+	statuses := make([]BudgetStatus, 0, len(categories))
+	for _, category := range categories {
+		statuses = append(statuses, computeBudgetStatus(category))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"budget_status": statuses,
+	})
+}
+
+// GetBudgetAlerts lists the BudgetAlert rows recorded for the user's
+// categories, most recent first.
+func GetBudgetAlerts(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+
+	var alerts []BudgetAlert
+	if err := DB.Where("user_id = ?", userID).Preload("Category").Order("created_at DESC").Find(&alerts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengambil budget alerts",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts": alerts,
+	})
+}
+
+// budgetAlertThresholds are checked from highest to lowest so a category that
+// jumps straight past 100% still gets both alerts recorded.
+var budgetAlertThresholds = []int{100, 80}
+
+// runBudgetAlertCheck writes a BudgetAlert row for every budgeted category
+// that has crossed 80%/100% of its budget in the active period, skipping
+// thresholds already recorded for that period (the unique index on
+// (category_id, period_start, threshold) makes this safe to call repeatedly).
+func runBudgetAlertCheck() {
+	var categories []Category
+	if err := DB.Where("budget > 0").Find(&categories).Error; err != nil {
+		log.Printf("budget alerts: failed to load budgeted categories: %v", err)
+		return
+	}
+
+	for _, category := range categories {
+		// A global category has no single owner to alert, so it can't cross a
+		// per-user budget threshold here.
+		if category.UserID == nil {
+			continue
+		}
+
+		status := computeBudgetStatus(category)
+		for _, threshold := range budgetAlertThresholds {
+			if status.PercentUsed < float64(threshold) {
+				continue
+			}
+
+			alert := BudgetAlert{
+				UserID:      *category.UserID,
+				CategoryID:  category.ID,
+				PeriodStart: status.PeriodStart,
+				Threshold:   threshold,
+				PercentUsed: status.PercentUsed,
+			}
+			if err := DB.Create(&alert).Error; err != nil {
+				// Most likely a duplicate for a threshold already alerted this period.
+				continue
+			}
+			break // don't also record the lower threshold once the higher one fires
+		}
+	}
+}
+
+// startNightlyBudgetAlertJob runs runBudgetAlertCheck once per interval.
+// Intended to be called as a goroutine with a ~24h interval.
+func startNightlyBudgetAlertJob(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		runBudgetAlertCheck()
+	}
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Config holds every runtime setting the app needs, resolved by the cmd
+// layer (cmd/server) from flags, environment variables, and config.yaml via
+// Viper, in that order of precedence. The app package itself never reads
+// os.Getenv directly for these anymore — see Configure.
+type Config struct {
+	Port string
+
+	// DatabaseDSN selects the driver via its URL scheme (see Connect/openDialector).
+	// When empty, the DB* fields below are used to build a Postgres DSN instead.
+	DatabaseDSN string
+	DBHost      string
+	DBPort      string
+	DBUser      string
+	DBPassword  string
+	DBName      string
+	DBSSLMode   string
+	DBTimezone  string
+
+	JWTSecret    string
+	AIServiceURL string
+
+	// CacheBackend/RedisAddr/RedisPassword mirror cache.New's CACHE_BACKEND/
+	// REDIS_ADDR/REDIS_PASSWORD env vars; Configure exports them so the cache
+	// package (which reads os.Getenv directly) still sees Viper-resolved values.
+	CacheBackend  string
+	RedisAddr     string
+	RedisPassword string
+
+	GinMode string
+}
+
+// Configure applies cfg to the package-level state that used to be read from
+// os.Getenv at package-init or call time (jwtSecret, aiServiceURL, and the
+// cache package's env vars). Call this once, after Viper has resolved
+// flags/env/config.yaml, before Connect or Serve.
+func Configure(cfg Config) {
+	jwtSecret = []byte(cfg.JWTSecret)
+	aiServiceURL = cfg.AIServiceURL
+
+	os.Setenv("CACHE_BACKEND", cfg.CacheBackend)
+	os.Setenv("REDIS_ADDR", cfg.RedisAddr)
+	os.Setenv("REDIS_PASSWORD", cfg.RedisPassword)
+	os.Setenv("GIN_MODE", cfg.GinMode)
+}
+
+// CreateAdminUser creates a user flagged as an admin, hashing password the
+// same way RegisterUser does. It's the backing function for `user create
+// --admin`, used to bootstrap the first administrator without going through
+// the public registration endpoint.
+func CreateAdminUser(username, password string) (*User, error) {
+	var existing User
+	if err := DB.Where("username = ?", username).First(&existing).Error; err == nil {
+		return nil, fmt.Errorf("user %q already exists", username)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := User{
+		Username: username,
+		Password: string(hashed),
+		Role:     RoleAdmin,
+	}
+	if err := DB.Create(&user).Error; err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,231 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var DB *gorm.DB
+
+// dbDialect identifies which SQL backend DB is talking to, since a few
+// migration/query paths need to branch on it (see Dialect).
+type dbDialect string
+
+const (
+	dialectPostgres  dbDialect = "postgres"
+	dialectMySQL     dbDialect = "mysql"
+	dialectSQLite    dbDialect = "sqlite"
+	dialectCockroach dbDialect = "cockroach"
+)
+
+var currentDialect dbDialect
+
+// Dialect returns the SQL backend the app connected to, so callers can gate
+// backend-specific DDL/query quirks (e.g. CockroachDB's SERIAL/SAVEPOINT
+// differences) without hardcoding a driver check everywhere.
+func Dialect() dbDialect {
+	return currentDialect
+}
+
+// Connect opens the database connection described by cfg. DATABASE_DSN's
+// scheme (postgres://, mysql://, sqlite://, cockroach://) selects the GORM
+// driver; CockroachDB speaks the Postgres wire protocol so it reuses that
+// driver with currentDialect flagged separately for its DDL quirks. This
+// replaces the old ConnectDatabase/getEnv pair now that Viper resolves
+// config precedence before the app package ever sees it.
+func Connect(cfg Config) error {
+	dsn := cfg.DatabaseDSN
+	if dsn == "" {
+		// Fallback DSN built from the discrete DB_* fields when no DSN is given.
+		dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
+			cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort, cfg.DBSSLMode, cfg.DBTimezone)
+	}
+
+	dialector, dialect, err := openDialector(dsn)
+	if err != nil {
+		return err
+	}
+	currentDialect = dialect
+
+	// Konfigurasi GORM
+	config := &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info),
+		// SQLite serializes writes behind a single file lock, so a SAVEPOINT
+		// issued for a nested DB.Transaction call (e.g. postTransactionEntries
+		// invoked from inside CreateTransaction's transaction) just adds
+		// contention without buying isolation; every other dialect here
+		// supports real nested transactions, so only SQLite opts out.
+		DisableNestedTransaction: Dialect() == dialectSQLite,
+	}
+
+	DB, err = gorm.Open(dialector, config)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// SQLite doesn't have a separate connection pool worth tuning; skip it.
+	if dialect != dialectSQLite {
+		sqlDB, err := DB.DB()
+		if err != nil {
+			return fmt.Errorf("failed to get database instance: %w", err)
+		}
+
+		// Set maksimum jumlah koneksi idle
+		sqlDB.SetMaxIdleConns(10)
+		// Set maksimum jumlah koneksi terbuka
+		sqlDB.SetMaxOpenConns(100)
+		// Set maksimum waktu hidup koneksi
+		// sqlDB.SetConnMaxLifetime(time.Hour)
+	}
+
+	log.Printf("Database connected successfully! (dialect: %s)", dialect)
+	return nil
+}
+
+// openDialector parses the DSN's URL scheme and returns the matching GORM
+// dialector. A bare DSN with no recognized scheme (e.g. the host=... key/value
+// form built above) is assumed to be Postgres for backwards compatibility.
+func openDialector(dsn string) (gorm.Dialector, dbDialect, error) {
+	scheme, rest, hasScheme := strings.Cut(dsn, "://")
+	if !hasScheme {
+		return postgres.Open(dsn), dialectPostgres, nil
+	}
+
+	switch scheme {
+	case "postgres", "postgresql":
+		return postgres.Open(dsn), dialectPostgres, nil
+	case "cockroach", "cockroachdb":
+		return postgres.Open("postgres://" + rest), dialectCockroach, nil
+	case "mysql":
+		return mysql.Open(rest), dialectMySQL, nil
+	case "sqlite", "sqlite3":
+		return sqlite.Open(rest), dialectSQLite, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported DATABASE_DSN scheme: %s", scheme)
+	}
+}
+
+// migratedModels lists every model `migrate` manages, in dependency order
+// (referenced tables first) so RunMigrations/MigrateDown/MigrationStatus stay
+// in lockstep with a single source of truth.
+var migratedModels = []interface{}{
+	&User{},
+	&Category{},
+	&Transaction{},
+	&Account{},
+	&Posting{},
+	&YNABAccount{},
+	&IdempotencyRecord{},
+	&BudgetAlert{},
+	&Session{},
+	&ExchangeRate{},
+	&SyncTombstone{},
+	&RecurringTransaction{},
+	&RecurringOccurrence{},
+	&ImportProfile{},
+	&ImportBatch{},
+	&ImportStagingRow{},
+	&ImportedTransaction{},
+	&Budget{},
+}
+
+// RunMigrations menjalankan auto-migration untuk semua model. This is the
+// "up" direction for `migrate`; there is no numbered migration history yet,
+// so it always converges the schema to the current model definitions.
+func RunMigrations() error {
+	log.Println("Running database migrations...")
+
+	if err := DB.AutoMigrate(migratedModels...); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	log.Println("Database migrations completed successfully!")
+	return nil
+}
+
+// MigrateDown drops the tables RunMigrations creates, in reverse dependency
+// order. GORM's AutoMigrate has no native rollback, so this is a best-effort
+// "down" for local/dev use, not a numbered-migration revert.
+func MigrateDown() error {
+	log.Println("Reverting database migrations...")
+
+	for i := len(migratedModels) - 1; i >= 0; i-- {
+		if err := DB.Migrator().DropTable(migratedModels[i]); err != nil {
+			return fmt.Errorf("failed to drop table for %T: %w", migratedModels[i], err)
+		}
+	}
+
+	log.Println("Database migrations reverted successfully!")
+	return nil
+}
+
+// MigrationStatus reports whether each managed model's table currently
+// exists, keyed by table name, so `migrate status` can show drift without
+// applying anything.
+func MigrationStatus() (map[string]bool, error) {
+	status := make(map[string]bool, len(migratedModels))
+	migrator := DB.Migrator()
+
+	for _, model := range migratedModels {
+		stmt := &gorm.Statement{DB: DB}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("failed to resolve table name for %T: %w", model, err)
+		}
+		status[stmt.Schema.Table] = migrator.HasTable(model)
+	}
+
+	return status, nil
+}
+
+// CreateDefaultCategories membuat kategori default untuk sistem
+func CreateDefaultCategories() error {
+	log.Println("Creating default categories...")
+
+	defaultCategories := []string{
+		"Makanan & Minuman",
+		"Transportasi",
+		"Tagihan",
+		"Belanja",
+		"Hiburan",
+		"Kesehatan",
+		"Pendidikan",
+		"Investasi",
+		"Lainnya",
+	}
+
+	for _, categoryName := range defaultCategories {
+		var existingCategory Category
+		if err := DB.Where("name = ? AND user_id IS NULL", categoryName).First(&existingCategory).Error; err == gorm.ErrRecordNotFound {
+			// Kategori belum ada, buat yang baru. UserID left nil, matching the
+			// "user_id IS NULL" lookup above (a *uint zero value is nil, not 0).
+			category := Category{
+				Name: categoryName,
+			}
+			if err := DB.Create(&category).Error; err != nil {
+				log.Printf("Failed to create default category '%s': %v", categoryName, err)
+			}
+		}
+	}
+
+	log.Println("Default categories created successfully!")
+	return nil
+}
+
+// CloseDatabase menutup koneksi database
+func CloseDatabase() error {
+	if DB != nil {
+		sqlDB, err := DB.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.Close()
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package app
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// monthPattern validates the "YYYY-MM" :month path params used throughout
+// this file.
+var monthPattern = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])$`)
+
+// monthBounds returns the [start, end) window covered by "YYYY-MM".
+func monthBounds(month string) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01", month)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// previousMonth returns the "YYYY-MM" preceding month.
+func previousMonth(month string) string {
+	start, _ := time.Parse("2006-01", month)
+	return start.AddDate(0, -1, 0).Format("2006-01")
+}
+
+// monthlyCategorySpent sums categoryID's expense transactions (negative
+// Amount, the same sign convention computeBudgetStatus uses) within month.
+func monthlyCategorySpent(userID, categoryID uint, month string) float64 {
+	start, end, err := monthBounds(month)
+	if err != nil {
+		return 0
+	}
+
+	var spent float64
+	DB.Model(&Transaction{}).
+		Where("user_id = ? AND category_id = ? AND amount < 0 AND transaction_date >= ? AND transaction_date < ?", userID, categoryID, start, end).
+		Select("COALESCE(SUM(ABS(amount)), 0)").
+		Scan(&spent)
+	return spent
+}
+
+// computeCarryover walks backward from month while each prior month's Budget
+// has Rollover set, accumulating Available into the next month's carryover.
+// It stops at the first month with no Budget row (nothing to carry from) or
+// Rollover=false (an explicit reset point). A negative Available is clamped
+// to 0 unless rolloverOverspend allows the deficit to carry forward too.
+func computeCarryover(userID, categoryID uint, month string, rolloverOverspend bool) float64 {
+	prevMonth := previousMonth(month)
+
+	var prevBudget Budget
+	if err := DB.Where("user_id = ? AND category_id = ? AND month = ?", userID, categoryID, prevMonth).First(&prevBudget).Error; err != nil {
+		return 0
+	}
+	if !prevBudget.Rollover {
+		return 0
+	}
+
+	prevCarryover := computeCarryover(userID, categoryID, prevMonth, rolloverOverspend)
+	spent := monthlyCategorySpent(userID, categoryID, prevMonth)
+	available := prevBudget.AssignedAmount + prevCarryover - spent
+
+	if available < 0 && !rolloverOverspend {
+		return 0
+	}
+	return available
+}
+
+// EnvelopeBudgetStatus is one category's computed spend-vs-plan snapshot for
+// GET /api/v1/budgets/:month/status.
+type EnvelopeBudgetStatus struct {
+	CategoryID   uint    `json:"category_id"`
+	CategoryName string  `json:"category"`
+	Assigned     float64 `json:"assigned"`
+	Spent        float64 `json:"spent"`
+	Carryover    float64 `json:"carryover"`
+	Available    float64 `json:"available"`
+	Rollover     bool    `json:"rollover"`
+}
+
+// GetBudgetsForMonth returns the caller's raw Budget rows for :month.
+func GetBudgetsForMonth(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	month := c.Param("month")
+	if !monthPattern.MatchString(month) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_month", Message: "month harus berformat YYYY-MM"})
+		return
+	}
+
+	var budgets []Budget
+	if err := DB.Where("user_id = ? AND month = ?", userID, month).Preload("Category").Find(&budgets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal mengambil budget"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"month": month, "budgets": budgets})
+}
+
+// SetBudgetRequest is one category's assignment in the PUT
+// /api/v1/budgets/:month body.
+type SetBudgetRequest struct {
+	CategoryID     uint    `json:"category_id" binding:"required"`
+	AssignedAmount float64 `json:"assigned_amount" binding:"min=0"`
+	Rollover       bool    `json:"rollover"`
+}
+
+// SetBudgetsForMonth upserts the caller's budget assignment for each
+// category_id in the request body for :month, leaving categories not
+// mentioned untouched.
+func SetBudgetsForMonth(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	uid := userID.(uint)
+
+	month := c.Param("month")
+	if !monthPattern.MatchString(month) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_month", Message: "month harus berformat YYYY-MM"})
+		return
+	}
+
+	var req struct {
+		Budgets []SetBudgetRequest `json:"budgets" binding:"required,min=1,dive"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "validation_error", Message: err.Error()})
+		return
+	}
+
+	saved := make([]Budget, 0, len(req.Budgets))
+	for _, entry := range req.Budgets {
+		var budget Budget
+		err := DB.Where("user_id = ? AND category_id = ? AND month = ?", uid, entry.CategoryID, month).First(&budget).Error
+		if err != nil {
+			budget = Budget{UserID: uid, CategoryID: entry.CategoryID, Month: month}
+		}
+		budget.AssignedAmount = entry.AssignedAmount
+		budget.Rollover = entry.Rollover
+
+		if err := DB.Save(&budget).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal menyimpan budget"})
+			return
+		}
+		saved = append(saved, budget)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Budget berhasil disimpan", "budgets": saved})
+}
+
+// GetBudgetStatusForMonth joins the caller's Budget rows for :month with
+// that month's actual category spend, computing carryover per category by
+// walking prior months (see computeCarryover).
+func GetBudgetStatusForMonth(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized", Message: "User ID tidak ditemukan"})
+		return
+	}
+	uid := userID.(uint)
+
+	month := c.Param("month")
+	if !monthPattern.MatchString(month) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_month", Message: "month harus berformat YYYY-MM"})
+		return
+	}
+
+	var user User
+	if err := DB.First(&user, uid).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal mengambil data user"})
+		return
+	}
+
+	var budgets []Budget
+	if err := DB.Where("user_id = ? AND month = ?", uid, month).Preload("Category").Find(&budgets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal mengambil budget"})
+		return
+	}
+
+	statuses := make([]EnvelopeBudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		spent := monthlyCategorySpent(uid, budget.CategoryID, month)
+
+		var carryover float64
+		if budget.Rollover {
+			carryover = computeCarryover(uid, budget.CategoryID, month, user.RolloverOverspend)
+		}
+
+		statuses = append(statuses, EnvelopeBudgetStatus{
+			CategoryID:   budget.CategoryID,
+			CategoryName: budget.Category.Name,
+			Assigned:     budget.AssignedAmount,
+			Spent:        spent,
+			Carryover:    carryover,
+			Available:    budget.AssignedAmount + carryover - spent,
+			Rollover:     budget.Rollover,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"month": month, "budget_status": statuses})
+}
+
+// CopyBudgetsFromPreviousMonth seeds :month's budgets from :prev_month,
+// copying each category's AssignedAmount/Rollover. Categories that already
+// have a budget row for :month are left alone rather than overwritten.
+func CopyBudgetsFromPreviousMonth(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	uid := userID.(uint)
+
+	month := c.Param("month")
+	prevMonth := c.Param("prev_month")
+	if !monthPattern.MatchString(month) || !monthPattern.MatchString(prevMonth) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_month", Message: "month dan prev_month harus berformat YYYY-MM"})
+		return
+	}
+
+	var sourceBudgets []Budget
+	if err := DB.Where("user_id = ? AND month = ?", uid, prevMonth).Find(&sourceBudgets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal mengambil budget bulan sebelumnya"})
+		return
+	}
+
+	copied := make([]Budget, 0, len(sourceBudgets))
+	for _, source := range sourceBudgets {
+		var existing Budget
+		err := DB.Where("user_id = ? AND category_id = ? AND month = ?", uid, source.CategoryID, month).First(&existing).Error
+		if err == nil {
+			continue // already budgeted for this category this month; don't clobber it
+		}
+
+		budget := Budget{
+			UserID:         uid,
+			CategoryID:     source.CategoryID,
+			Month:          month,
+			AssignedAmount: source.AssignedAmount,
+			Rollover:       source.Rollover,
+		}
+		if err := DB.Create(&budget).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal menyalin budget"})
+			return
+		}
+		copied = append(copied, budget)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Budget berhasil disalin dari bulan sebelumnya",
+		"copied":  copied,
+	})
+}
@@ -0,0 +1,244 @@
+package app
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const exportBatchSize = 200
+
+// ExportTransactions streams the user's transactions in one of several
+// formats so they can be imported straight into desktop finance software.
+// Supported via ?format=: csv (default), ofx, qif, jsonapi.
+func ExportTransactions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+
+	startDate := c.Query("start_date")
+	endDate := c.Query("end_date")
+	categoryID := c.Query("category_id")
+	format := c.DefaultQuery("format", "csv")
+
+	query := DB.Where("user_id = ?", userID).Preload("Category")
+	if categoryID != "" {
+		query = query.Where("category_id = ?", categoryID)
+	}
+	if startDate != "" {
+		query = query.Where("transaction_date >= ?", startDate)
+	}
+	if endDate != "" {
+		query = query.Where("transaction_date <= ?", endDate)
+	}
+	query = query.Order("transaction_date DESC")
+
+	switch format {
+	case "csv":
+		exportTransactionsCSV(c, query)
+	case "ofx":
+		exportTransactionsOFX(c, query)
+	case "qif":
+		exportTransactionsQIF(c, query)
+	case "jsonapi":
+		exportTransactionsJSONAPI(c, query)
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_format",
+			Message: "format harus salah satu dari: csv, ofx, qif, jsonapi",
+		})
+	}
+}
+
+func categoryNameOf(txn *Transaction) string {
+	if txn.Category != nil {
+		return txn.Category.Name
+	}
+	return "Tidak Berkategori"
+}
+
+func flushIfPossible(w http.ResponseWriter) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// exportTransactionsCSV streams rows via encoding/csv, which handles quoting
+// of descriptions containing commas/quotes/newlines correctly, unlike the
+// c.String-based writer this replaced.
+func exportTransactionsCSV(c *gin.Context, query *gorm.DB) {
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", "attachment; filename=transactions.csv")
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"ID", "Description", "Amount", "Category", "Transaction Date", "Created At"})
+
+	var transactions []Transaction
+	query.FindInBatches(&transactions, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, txn := range transactions {
+			row := []string{
+				strconv.FormatUint(uint64(txn.ID), 10),
+				txn.Description,
+				strconv.FormatFloat(txn.Amount, 'f', 2, 64),
+				categoryNameOf(&txn),
+				txn.TransactionDate.Format("2006-01-02"),
+				txn.CreatedAt.Format("2006-01-02 15:04:05"),
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return nil
+	})
+	writer.Flush()
+}
+
+// exportTransactionsOFX writes OFX 2.x (the XML variant) with one STMTTRN per
+// transaction, streamed batch by batch.
+func exportTransactionsOFX(c *gin.Context, query *gorm.DB) {
+	c.Header("Content-Type", "application/x-ofx")
+	c.Header("Content-Disposition", "attachment; filename=transactions.ofx")
+
+	fmt.Fprint(c.Writer, `<?xml version="1.0" encoding="UTF-8"?>
+<OFX>
+<BANKMSGSRSV1>
+<STMTTRNRS>
+<STMTRS>
+<BANKTRANLIST>
+`)
+
+	var transactions []Transaction
+	query.FindInBatches(&transactions, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, txn := range transactions {
+			trnType := "DEBIT"
+			if txn.Amount > 0 {
+				trnType = "CREDIT"
+			}
+			fmt.Fprintf(c.Writer, `<STMTTRN>
+<TRNTYPE>%s</TRNTYPE>
+<DTPOSTED>%s</DTPOSTED>
+<TRNAMT>%.2f</TRNAMT>
+<FITID>%d</FITID>
+<NAME>%s</NAME>
+<MEMO>%s</MEMO>
+</STMTTRN>
+`, trnType, txn.TransactionDate.Format("20060102"), txn.Amount, txn.ID, ofxEscape(txn.Description), ofxEscape(categoryNameOf(&txn)))
+		}
+		flushIfPossible(c.Writer)
+		return nil
+	})
+
+	fmt.Fprint(c.Writer, `</BANKTRANLIST>
+</STMTRS>
+</STMTTRNRS>
+</BANKMSGSRSV1>
+</OFX>
+`)
+}
+
+func ofxEscape(s string) string {
+	s = strings.ReplaceAll(s, "&", "&amp;")
+	s = strings.ReplaceAll(s, "<", "&lt;")
+	s = strings.ReplaceAll(s, ">", "&gt;")
+	return s
+}
+
+// qifEscape strips characters QIF's one-field-per-line format can't carry,
+// so a Description with an embedded newline can't spill into the next field.
+func qifEscape(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "\r", " ")
+	return s
+}
+
+// exportTransactionsQIF writes the Quicken text interchange format.
+func exportTransactionsQIF(c *gin.Context, query *gorm.DB) {
+	c.Header("Content-Type", "application/qif")
+	c.Header("Content-Disposition", "attachment; filename=transactions.qif")
+
+	fmt.Fprint(c.Writer, "!Type:Bank\n")
+
+	var transactions []Transaction
+	query.FindInBatches(&transactions, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, txn := range transactions {
+			fmt.Fprintf(c.Writer, "D%s\nT%.2f\nP%s\nL%s\n^\n",
+				txn.TransactionDate.Format("01/02/2006"),
+				txn.Amount,
+				qifEscape(txn.Description),
+				qifEscape(categoryNameOf(&txn)),
+			)
+		}
+		flushIfPossible(c.Writer)
+		return nil
+	})
+}
+
+// exportTransactionsJSONAPI writes a JSON:API document, streaming the `data`
+// array batch by batch so large exports don't have to be buffered in memory.
+func exportTransactionsJSONAPI(c *gin.Context, query *gorm.DB) {
+	c.Header("Content-Type", "application/vnd.api+json")
+	c.Header("Content-Disposition", "attachment; filename=transactions.jsonapi.json")
+
+	type resourceID struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	}
+	type relationship struct {
+		Data *resourceID `json:"data"`
+	}
+	type resource struct {
+		Type          string                  `json:"type"`
+		ID            string                  `json:"id"`
+		Attributes    map[string]interface{}  `json:"attributes"`
+		Relationships map[string]relationship `json:"relationships,omitempty"`
+	}
+
+	fmt.Fprint(c.Writer, `{"data":[`)
+
+	first := true
+	encoder := json.NewEncoder(c.Writer)
+
+	var transactions []Transaction
+	query.FindInBatches(&transactions, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		for _, txn := range transactions {
+			if !first {
+				fmt.Fprint(c.Writer, ",")
+			}
+			first = false
+
+			res := resource{
+				Type: "transactions",
+				ID:   strconv.FormatUint(uint64(txn.ID), 10),
+				Attributes: map[string]interface{}{
+					"description":      txn.Description,
+					"amount":           txn.Amount,
+					"transaction_date": txn.TransactionDate,
+					"created_at":       txn.CreatedAt,
+				},
+			}
+			if txn.CategoryID != nil {
+				res.Relationships = map[string]relationship{
+					"category": {Data: &resourceID{Type: "categories", ID: strconv.FormatUint(uint64(*txn.CategoryID), 10)}},
+				}
+			}
+			encoder.Encode(res)
+		}
+		flushIfPossible(c.Writer)
+		return nil
+	})
+
+	fmt.Fprint(c.Writer, `]}`)
+}
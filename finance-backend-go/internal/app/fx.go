@@ -0,0 +1,240 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FXRateFetcher pulls a single day's FromCurrency->ToCurrency rate from an
+// external source. Implementations are hand-rolled clients in the same style
+// as ynab.go rather than a generated SDK, since RefreshExchangeRates only
+// ever needs this one call.
+type FXRateFetcher interface {
+	FetchRate(ctx context.Context, from, to string) (float64, error)
+}
+
+// exchangeRateHostFetcher hits exchangerate.host's free, keyless latest-rate
+// endpoint. It's the default FXRateFetcher; swap in an ECB-backed one by
+// implementing the same interface if a paid/more precise source is needed.
+type exchangeRateHostFetcher struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+func newExchangeRateHostFetcher() *exchangeRateHostFetcher {
+	return &exchangeRateHostFetcher{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		baseURL:    "https://api.exchangerate.host",
+	}
+}
+
+type exchangeRateHostResponse struct {
+	Success bool               `json:"success"`
+	Rates   map[string]float64 `json:"rates"`
+}
+
+func (f *exchangeRateHostFetcher) FetchRate(ctx context.Context, from, to string) (float64, error) {
+	endpoint := fmt.Sprintf("%s/latest?base=%s&symbols=%s", f.baseURL, from, to)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create FX request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reach FX rate provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read FX response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("FX rate provider error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	var parsed exchangeRateHostResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return 0, fmt.Errorf("failed to parse FX response: %w", err)
+	}
+
+	rate, ok := parsed.Rates[to]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("no rate returned for %s->%s", from, to)
+	}
+	return rate, nil
+}
+
+// defaultFXFetcher is what RefreshExchangeRates uses unless a caller
+// substitutes another FXRateFetcher (e.g. in tests).
+var defaultFXFetcher FXRateFetcher = newExchangeRateHostFetcher()
+
+// RefreshExchangeRates fetches today's base->target rate for every target
+// via fetcher and upserts it, meant to be run daily (cron, or a scheduler
+// goroutine analogous to startYNABAutoSyncScheduler). See
+// startFXRefreshScheduler for the goroutine that actually calls this.
+func RefreshExchangeRates(fetcher FXRateFetcher, base string, targets []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	today := truncateToDay(time.Now())
+
+	for _, target := range targets {
+		if target == base {
+			continue
+		}
+		rate, err := fetcher.FetchRate(ctx, base, target)
+		if err != nil {
+			log.Printf("FX refresh: failed to fetch %s->%s: %v", base, target, err)
+			continue
+		}
+		if err := upsertExchangeRate(base, target, today, rate); err != nil {
+			log.Printf("FX refresh: failed to store %s->%s: %v", base, target, err)
+		}
+	}
+
+	return nil
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func upsertExchangeRate(from, to string, date time.Time, rate float64) error {
+	var existing ExchangeRate
+	err := DB.Where("from_currency = ? AND to_currency = ? AND date = ?", from, to, date).First(&existing).Error
+	if err == nil {
+		existing.Rate = rate
+		return DB.Save(&existing).Error
+	}
+	return DB.Create(&ExchangeRate{FromCurrency: from, ToCurrency: to, Date: date, Rate: rate}).Error
+}
+
+// lookupExchangeRate resolves the from->to rate to use for a transaction
+// dated on date. It tries, in order: identity (from == to), the
+// nearest-previous stored rate (same day or earlier, closest first), the
+// nearest-previous inverse rate (to->from, inverted), and finally falls back
+// to identity (rate 1) so a missing rate degrades to "don't convert" rather
+// than failing the request.
+func lookupExchangeRate(from, to string, date time.Time) (rate float64, err error) {
+	if from == to {
+		return 1, nil
+	}
+
+	day := truncateToDay(date)
+
+	var direct ExchangeRate
+	if err := DB.Where("from_currency = ? AND to_currency = ? AND date <= ?", from, to, day).
+		Order("date DESC").First(&direct).Error; err == nil {
+		return direct.Rate, nil
+	}
+
+	var inverse ExchangeRate
+	if err := DB.Where("from_currency = ? AND to_currency = ? AND date <= ?", to, from, day).
+		Order("date DESC").First(&inverse).Error; err == nil && inverse.Rate != 0 {
+		return 1 / inverse.Rate, nil
+	}
+
+	log.Printf("FX lookup: no rate found for %s->%s as of %s, falling back to identity", from, to, day.Format("2006-01-02"))
+	return 1, nil
+}
+
+// convertToBaseCurrency converts amount in fromCurrency, dated on date, into
+// toCurrency, returning both the converted amount and the rate used so
+// callers (TransactionResponse) can display the original + converted figure.
+func convertToBaseCurrency(amount float64, fromCurrency, toCurrency string, date time.Time) (converted float64, rate float64) {
+	rate, _ = lookupExchangeRate(fromCurrency, toCurrency, date)
+	return amount * rate, rate
+}
+
+// userBaseCurrency looks up userID's BaseCurrency, defaulting to "IDR" if the
+// user can't be loaded (should only happen for a stale/deleted userID).
+func userBaseCurrency(userID uint) string {
+	var user User
+	if err := DB.Select("base_currency").First(&user, userID).Error; err != nil {
+		return "IDR"
+	}
+	if user.BaseCurrency == "" {
+		return "IDR"
+	}
+	return user.BaseCurrency
+}
+
+// SetExchangeRateRequest is the body for POST /api/v1/fx/rates: an
+// admin manually recording (or overriding) one day's rate, supplementing
+// whatever RefreshExchangeRates pulled automatically.
+type SetExchangeRateRequest struct {
+	Date         time.Time `json:"date" binding:"required"`
+	FromCurrency string    `json:"from_currency" binding:"required,len=3"`
+	ToCurrency   string    `json:"to_currency" binding:"required,len=3"`
+	Rate         float64   `json:"rate" binding:"required,gt=0"`
+}
+
+// AdminSetExchangeRate lets an admin record a day's FX rate by hand, for
+// currency pairs or historical dates the automatic fetcher doesn't cover.
+func AdminSetExchangeRate(c *gin.Context) {
+	var req SetExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := upsertExchangeRate(req.FromCurrency, req.ToCurrency, truncateToDay(req.Date), req.Rate); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal menyimpan kurs",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Kurs berhasil disimpan",
+	})
+}
+
+// startFXRefreshScheduler periodically calls refreshAllExchangeRates. Runs
+// for the lifetime of the process; call from Serve as a goroutine.
+func startFXRefreshScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		refreshAllExchangeRates()
+	}
+}
+
+// refreshAllExchangeRates pulls today's rate for every BaseCurrency/
+// transaction-Currency pair currently in use, so users never have to
+// populate rates by hand via AdminSetExchangeRate just to get conversion.
+func refreshAllExchangeRates() {
+	var baseCurrencies []string
+	if err := DB.Model(&User{}).Distinct().Pluck("base_currency", &baseCurrencies).Error; err != nil {
+		log.Printf("FX refresh: failed to load base currencies: %v", err)
+		return
+	}
+
+	var targets []string
+	if err := DB.Model(&Transaction{}).Distinct().Pluck("currency", &targets).Error; err != nil {
+		log.Printf("FX refresh: failed to load transaction currencies: %v", err)
+		return
+	}
+
+	for _, base := range baseCurrencies {
+		if err := RefreshExchangeRates(defaultFXFetcher, base, targets); err != nil {
+			log.Printf("FX refresh: failed for base %s: %v", base, err)
+		}
+	}
+}
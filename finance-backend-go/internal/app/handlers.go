@@ -1,4 +1,4 @@
-package main
+package app
 
 import (
 	"net/http"
@@ -11,11 +11,22 @@ import (
 
 // JWT Claims structure
 type Claims struct {
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	SessionID uint   `json:"session_id"`
+	Role      Role   `json:"role"`
+	// AAL and ReauthAt are only set on a token minted by Reauthenticate; a
+	// normal login/refresh token leaves both zero-valued. RequireReauth reads
+	// ReauthAt to gate sensitive handlers behind a recently-verified password.
+	AAL      string `json:"aal,omitempty"`
+	ReauthAt int64  `json:"reauth_at,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// accessTokenTTL is short-lived by design; POST /auth/refresh mints a new one
+// from the long-lived refresh token instead of extending this one.
+const accessTokenTTL = 15 * time.Minute
+
 // Request/Response structures
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
@@ -28,10 +39,11 @@ type LoginRequest struct {
 }
 
 type AuthResponse struct {
-	Token    string `json:"token"`
-	UserID   uint   `json:"user_id"`
-	Username string `json:"username"`
-	Message  string `json:"message"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	UserID       uint   `json:"user_id"`
+	Username     string `json:"username"`
+	Message      string `json:"message"`
 }
 
 type ErrorResponse struct {
@@ -39,7 +51,11 @@ type ErrorResponse struct {
 	Message string `json:"message"`
 }
 
-var jwtSecret = []byte(getEnv("JWT_SECRET", "your-secret-key"))
+// jwtSecret defaults to the historical fallback and is overwritten by
+// Configure once the cmd layer has resolved flags/config.yaml/env through
+// Viper; the default keeps token signing safe even if Configure is never
+// called (e.g. by a future caller that skips the cmd/server entrypoint).
+var jwtSecret = []byte("your-secret-key")
 
 // RegisterUser handles user registration
 func RegisterUser(c *gin.Context) {
@@ -88,8 +104,17 @@ func RegisterUser(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := generateJWT(user.ID, user.Username)
+	// Create a session + refresh token and issue a short-lived access JWT scoped to it
+	session, rawRefreshToken, err := createSession(user.ID, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "session_error",
+			Message: "Gagal membuat session",
+		})
+		return
+	}
+
+	token, err := generateJWT(user.ID, user.Username, user.Role, session.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "token_error",
@@ -99,10 +124,11 @@ func RegisterUser(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusCreated, AuthResponse{
-		Token:    token,
-		UserID:   user.ID,
-		Username: user.Username,
-		Message:  "User berhasil didaftarkan",
+		Token:        token,
+		RefreshToken: rawRefreshToken,
+		UserID:       user.ID,
+		Username:     user.Username,
+		Message:      "User berhasil didaftarkan",
 	})
 }
 
@@ -138,8 +164,17 @@ func LoginUser(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := generateJWT(user.ID, user.Username)
+	// Create a session + refresh token and issue a short-lived access JWT scoped to it
+	session, rawRefreshToken, err := createSession(user.ID, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "session_error",
+			Message: "Gagal membuat session",
+		})
+		return
+	}
+
+	token, err := generateJWT(user.ID, user.Username, user.Role, session.ID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "token_error",
@@ -149,22 +184,28 @@ func LoginUser(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, AuthResponse{
-		Token:    token,
-		UserID:   user.ID,
-		Username: user.Username,
-		Message:  "Login berhasil",
+		Token:        token,
+		RefreshToken: rawRefreshToken,
+		UserID:       user.ID,
+		Username:     user.Username,
+		Message:      "Login berhasil",
 	})
 }
 
-// generateJWT creates a new JWT token
-func generateJWT(userID uint, username string) (string, error) {
-	// Set token expiration (24 hours)
-	expirationTime := time.Now().Add(24 * time.Hour)
+// generateJWT creates a new short-lived access token scoped to sessionID, so
+// revoking that session (logout, reuse detection) is picked up by
+// JWTMiddleware before the token would otherwise expire on its own. role is
+// stamped in at issue time (rather than looked up per-request) so
+// RequireRole doesn't cost a database round trip.
+func generateJWT(userID uint, username string, role Role, sessionID uint) (string, error) {
+	expirationTime := time.Now().Add(accessTokenTTL)
 
 	// Create claims
 	claims := &Claims{
-		UserID:   userID,
-		Username: username,
+		UserID:    userID,
+		Username:  username,
+		SessionID: sessionID,
+		Role:      role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,148 @@
+package app
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const idempotencyRecordTTL = 24 * time.Hour
+
+// bodyCaptureWriter mirrors everything written to the real ResponseWriter into
+// an in-memory buffer so IdempotencyMiddleware can persist the final response.
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	buf *bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	w.buf.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.buf.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware short-circuits retried requests that carry the same
+// Idempotency-Key header as a prior request, replaying the cached response
+// instead of re-running the handler. Requests without the header pass through
+// unchanged. Must be registered before handlers that have non-idempotent side
+// effects (e.g. category creation in RecategorizeTransaction).
+func IdempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		userIDVal, exists := c.Get("user_id")
+		if !exists {
+			c.Next()
+			return
+		}
+		userID := userIDVal.(uint)
+
+		bodyBytes, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_body",
+				Message: "Gagal membaca request body",
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+
+		hash := sha256.Sum256([]byte(fmt.Sprintf("%d:%s:%s:%s", userID, c.Request.Method, c.FullPath(), bodyBytes)))
+		requestHash := hex.EncodeToString(hash[:])
+
+		var existing IdempotencyRecord
+		err = DB.Where("user_id = ? AND idempotency_key = ?", userID, key).First(&existing).Error
+
+		switch {
+		case err == nil && existing.InFlight:
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "request_in_progress",
+				Message: "Request dengan Idempotency-Key ini sedang diproses",
+			})
+			c.Abort()
+			return
+
+		case err == nil && existing.RequestHash != requestHash:
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "idempotency_key_reused",
+				Message: "Idempotency-Key sudah dipakai untuk request yang berbeda",
+			})
+			c.Abort()
+			return
+
+		case err == nil:
+			// Completed request with matching payload: replay the cached response.
+			c.Data(existing.StatusCode, "application/json", []byte(existing.ResponseBody))
+			c.Abort()
+			return
+
+		case err != gorm.ErrRecordNotFound:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "database_error",
+				Message: "Gagal memeriksa Idempotency-Key",
+			})
+			c.Abort()
+			return
+		}
+
+		record := IdempotencyRecord{
+			UserID:         userID,
+			IdempotencyKey: key,
+			RequestHash:    requestHash,
+			InFlight:       true,
+			ExpiresAt:      time.Now().Add(idempotencyRecordTTL),
+		}
+		if err := DB.Create(&record).Error; err != nil {
+			// Most likely a race where a concurrent request just inserted the
+			// same (user_id, key) pair: treat it as an in-flight duplicate.
+			c.JSON(http.StatusConflict, ErrorResponse{
+				Error:   "request_in_progress",
+				Message: "Request dengan Idempotency-Key ini sedang diproses",
+			})
+			c.Abort()
+			return
+		}
+
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer, buf: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		record.InFlight = false
+		record.StatusCode = c.Writer.Status()
+		record.ResponseBody = writer.buf.String()
+		if err := DB.Save(&record).Error; err != nil {
+			log.Printf("idempotency: failed to persist response for key %s: %v", key, err)
+		}
+	}
+}
+
+// pruneExpiredIdempotencyRecords periodically deletes idempotency records past
+// their TTL so the table doesn't grow unbounded.
+func pruneExpiredIdempotencyRecords(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := DB.Where("expires_at < ?", time.Now()).Delete(&IdempotencyRecord{}).Error; err != nil {
+			log.Printf("idempotency: failed to prune expired records: %v", err)
+		}
+	}
+}
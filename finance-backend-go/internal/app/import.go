@@ -0,0 +1,562 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var validImportFormats = map[string]bool{"ofx": true, "qif": true, "csv": true}
+
+const (
+	importBatchStaged    = "staged"
+	importBatchCommitted = "committed"
+
+	importRowNew         = "new"
+	importRowDuplicate   = "duplicate"
+	importRowConflicting = "conflicting"
+)
+
+// parsedImportRow is one bank transaction as parsed out of an OFX/QIF/CSV
+// upload, before it's staged as an ImportStagingRow.
+type parsedImportRow struct {
+	Date        time.Time
+	Amount      float64
+	Description string
+	FITID       string
+}
+
+// normalizeDescription lowercases and collapses whitespace so trivial
+// formatting differences between a re-exported bank file and a previous
+// import don't defeat fingerprint matching.
+func normalizeDescription(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// computeImportFingerprint hashes (date, amount, normalized description,
+// account) per the request: the stable identity of a bank transaction across
+// re-exports, used to dedupe against both existing Transactions and prior
+// ImportedTransaction rows.
+func computeImportFingerprint(date time.Time, amount float64, description, account string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%.2f|%s|%s", date.Format("2006-01-02"), amount, normalizeDescription(description), strings.ToLower(strings.TrimSpace(account)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// classifyImportRow decides whether a parsed row is new, an exact duplicate
+// of something already imported or entered, or merely conflicting (same
+// date+amount, different description — worth a second look but not dropped
+// automatically).
+func classifyImportRow(userID uint, fingerprint, fitid string, date time.Time, amount float64, description string) string {
+	var priorImportCount int64
+	priorQuery := DB.Model(&ImportedTransaction{}).Where("user_id = ? AND fingerprint = ?", userID, fingerprint)
+	if fitid != "" {
+		priorQuery = DB.Model(&ImportedTransaction{}).
+			Where("user_id = ? AND (fingerprint = ? OR fitid = ?)", userID, fingerprint, fitid)
+	}
+	priorQuery.Count(&priorImportCount)
+	if priorImportCount > 0 {
+		return importRowDuplicate
+	}
+
+	var existingExact int64
+	DB.Model(&Transaction{}).
+		Where("user_id = ? AND transaction_date = ? AND amount = ? AND description = ?", userID, date, amount, description).
+		Count(&existingExact)
+	if existingExact > 0 {
+		return importRowDuplicate
+	}
+
+	var existingSameDayAmount int64
+	DB.Model(&Transaction{}).
+		Where("user_id = ? AND transaction_date = ? AND amount = ?", userID, date, amount).
+		Count(&existingSameDayAmount)
+	if existingSameDayAmount > 0 {
+		return importRowConflicting
+	}
+
+	return importRowNew
+}
+
+// ofxStmtTrnPattern matches one <STMTTRN>...</STMTTRN> block; ofxTagValue
+// pulls a single field out of it. OFX 2.x's XML form (the one ExportTransactions
+// writes) always closes its tags, so this simple scan is enough without a
+// full XML parser.
+var ofxStmtTrnPattern = regexp.MustCompile(`(?s)<STMTTRN>(.*?)</STMTTRN>`)
+
+func ofxTagValue(block, tag string) string {
+	re := regexp.MustCompile(`<` + tag + `>([^<\r\n]*)`)
+	m := re.FindStringSubmatch(block)
+	if len(m) < 2 {
+		return ""
+	}
+	return strings.TrimSpace(m[1])
+}
+
+// parseOFXDate reads OFX's DTPOSTED, which is YYYYMMDD optionally followed
+// by a time/timezone suffix this import only needs to discard.
+func parseOFXDate(s string) (time.Time, error) {
+	if len(s) < 8 {
+		return time.Time{}, fmt.Errorf("tanggal OFX tidak valid: %q", s)
+	}
+	return time.Parse("20060102", s[:8])
+}
+
+// parseOFX extracts one parsedImportRow per <STMTTRN> block.
+func parseOFX(data []byte) ([]parsedImportRow, error) {
+	var rows []parsedImportRow
+	for _, match := range ofxStmtTrnPattern.FindAllStringSubmatch(string(data), -1) {
+		block := match[1]
+
+		date, err := parseOFXDate(ofxTagValue(block, "DTPOSTED"))
+		if err != nil {
+			continue
+		}
+		amount, err := strconv.ParseFloat(ofxTagValue(block, "TRNAMT"), 64)
+		if err != nil {
+			continue
+		}
+
+		description := ofxTagValue(block, "NAME")
+		if description == "" {
+			description = ofxTagValue(block, "MEMO")
+		}
+
+		rows = append(rows, parsedImportRow{
+			Date:        date,
+			Amount:      amount,
+			Description: description,
+			FITID:       ofxTagValue(block, "FITID"),
+		})
+	}
+	return rows, nil
+}
+
+// qifDateLayouts are the date formats QIF exports from different apps are
+// commonly seen in.
+var qifDateLayouts = []string{"01/02/2006", "1/2/2006", "01/02/'06", "2006-01-02"}
+
+func parseQIFDate(s string) (time.Time, error) {
+	for _, layout := range qifDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("tanggal QIF tidak valid: %q", s)
+}
+
+// parseQIF reads Quicken's !Type:Bank-style records: one transaction per
+// block of D/T/P/M lines terminated by a lone "^".
+func parseQIF(data []byte) ([]parsedImportRow, error) {
+	var rows []parsedImportRow
+	var date time.Time
+	var amount float64
+	var description string
+	var haveDate, haveAmount bool
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || line[0] == '!' {
+			continue
+		}
+
+		switch line[0] {
+		case '^':
+			if haveDate && haveAmount {
+				rows = append(rows, parsedImportRow{Date: date, Amount: amount, Description: description})
+			}
+			date, amount, description = time.Time{}, 0, ""
+			haveDate, haveAmount = false, false
+		case 'D':
+			if t, err := parseQIFDate(line[1:]); err == nil {
+				date = t
+				haveDate = true
+			}
+		case 'T', 'U':
+			if amt, err := strconv.ParseFloat(strings.ReplaceAll(line[1:], ",", ""), 64); err == nil {
+				amount = amt
+				haveAmount = true
+			}
+		case 'P':
+			description = line[1:]
+		case 'M':
+			if description == "" {
+				description = line[1:]
+			}
+		}
+	}
+	return rows, scanner.Err()
+}
+
+// csvColumnMapping names which CSV header identifies the date/amount/
+// description of each row, either resolved from a saved ImportProfile or
+// passed inline on the upload.
+type csvColumnMapping struct {
+	DateColumn        string
+	AmountColumn      string
+	DescriptionColumn string
+	DateFormat        string
+}
+
+// parseCSV reads data as a header + rows CSV, mapping columns by header name
+// (case-insensitive) per mapping.
+func parseCSV(data []byte, mapping csvColumnMapping) ([]parsedImportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca header CSV: %w", err)
+	}
+
+	columnIndex := make(map[string]int, len(header))
+	for i, name := range header {
+		columnIndex[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	dateIdx, ok := columnIndex[strings.ToLower(mapping.DateColumn)]
+	if !ok {
+		return nil, fmt.Errorf("kolom tanggal %q tidak ditemukan di header CSV", mapping.DateColumn)
+	}
+	amountIdx, ok := columnIndex[strings.ToLower(mapping.AmountColumn)]
+	if !ok {
+		return nil, fmt.Errorf("kolom jumlah %q tidak ditemukan di header CSV", mapping.AmountColumn)
+	}
+	descriptionIdx, ok := columnIndex[strings.ToLower(mapping.DescriptionColumn)]
+	if !ok {
+		return nil, fmt.Errorf("kolom deskripsi %q tidak ditemukan di header CSV", mapping.DescriptionColumn)
+	}
+
+	dateFormat := mapping.DateFormat
+	if dateFormat == "" {
+		dateFormat = "2006-01-02"
+	}
+
+	var rows []parsedImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gagal membaca baris CSV: %w", err)
+		}
+		if dateIdx >= len(record) || amountIdx >= len(record) || descriptionIdx >= len(record) {
+			continue
+		}
+
+		date, err := time.Parse(dateFormat, strings.TrimSpace(record[dateIdx]))
+		if err != nil {
+			continue
+		}
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(record[amountIdx]), ",", ""), 64)
+		if err != nil {
+			continue
+		}
+
+		rows = append(rows, parsedImportRow{Date: date, Amount: amount, Description: strings.TrimSpace(record[descriptionIdx])})
+	}
+	return rows, nil
+}
+
+// resolveCSVMapping reads the column mapping for a CSV upload, either from a
+// saved ImportProfile (form field profile_id) or inline form fields.
+func resolveCSVMapping(c *gin.Context, userID uint) (csvColumnMapping, error) {
+	if profileID := c.PostForm("profile_id"); profileID != "" {
+		var profile ImportProfile
+		if err := DB.Where("id = ? AND user_id = ?", profileID, userID).First(&profile).Error; err != nil {
+			return csvColumnMapping{}, fmt.Errorf("import profile tidak ditemukan")
+		}
+		return csvColumnMapping{
+			DateColumn:        profile.DateColumn,
+			AmountColumn:      profile.AmountColumn,
+			DescriptionColumn: profile.DescriptionColumn,
+			DateFormat:        profile.DateFormat,
+		}, nil
+	}
+
+	mapping := csvColumnMapping{
+		DateColumn:        c.PostForm("date_column"),
+		AmountColumn:      c.PostForm("amount_column"),
+		DescriptionColumn: c.PostForm("description_column"),
+		DateFormat:        c.PostForm("date_format"),
+	}
+	if mapping.DateColumn == "" || mapping.AmountColumn == "" || mapping.DescriptionColumn == "" {
+		return csvColumnMapping{}, fmt.Errorf("format csv membutuhkan profile_id, atau date_column/amount_column/description_column")
+	}
+	return mapping, nil
+}
+
+// CreateImportProfileRequest is the body for POST /api/v1/import/profiles.
+type CreateImportProfileRequest struct {
+	Name              string `json:"name" binding:"required,max=100"`
+	DateColumn        string `json:"date_column" binding:"required"`
+	AmountColumn      string `json:"amount_column" binding:"required"`
+	DescriptionColumn string `json:"description_column" binding:"required"`
+	DateFormat        string `json:"date_format"`
+}
+
+// CreateImportProfile saves a CSV column mapping the caller can reuse across
+// uploads via profile_id instead of resending the mapping every time.
+func CreateImportProfile(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	uid := userID.(uint)
+
+	var req CreateImportProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "validation_error", Message: err.Error()})
+		return
+	}
+
+	profile := ImportProfile{
+		UserID:            uid,
+		Name:              req.Name,
+		DateColumn:        req.DateColumn,
+		AmountColumn:      req.AmountColumn,
+		DescriptionColumn: req.DescriptionColumn,
+		DateFormat:        req.DateFormat,
+	}
+	if err := DB.Create(&profile).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal menyimpan import profile"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Import profile berhasil disimpan", "profile": profile})
+}
+
+// GetImportProfiles lists the caller's saved CSV column mappings.
+func GetImportProfiles(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var profiles []ImportProfile
+	if err := DB.Where("user_id = ?", userID).Find(&profiles).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal mengambil import profiles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"profiles": profiles})
+}
+
+// CreateImportBatch handles POST /api/v1/import: a multipart upload of a
+// bank export, parsed into a canonical staging structure and classified
+// new/duplicate/conflicting against existing transactions and prior
+// imports. Nothing is written to Transaction yet — see CommitImportBatch.
+func CreateImportBatch(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	uid := userID.(uint)
+
+	format := c.PostForm("format")
+	if !validImportFormats[format] {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_format", Message: "format harus salah satu dari: ofx, qif, csv"})
+		return
+	}
+	account := c.PostForm("account")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "missing_file", Message: "File upload tidak ditemukan"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "file_error", Message: "Gagal membuka file upload"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "file_error", Message: "Gagal membaca file upload"})
+		return
+	}
+
+	var parsed []parsedImportRow
+	switch format {
+	case "ofx":
+		parsed, err = parseOFX(data)
+	case "qif":
+		parsed, err = parseQIF(data)
+	case "csv":
+		var mapping csvColumnMapping
+		mapping, err = resolveCSVMapping(c, uid)
+		if err == nil {
+			parsed, err = parseCSV(data, mapping)
+		}
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "parse_error", Message: "Gagal mem-parsing file: " + err.Error()})
+		return
+	}
+
+	batch := ImportBatch{UserID: uid, Format: format, Account: account, Status: importBatchStaged}
+	if err := DB.Create(&batch).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal membuat batch import"})
+		return
+	}
+
+	summary := map[string]int{importRowNew: 0, importRowDuplicate: 0, importRowConflicting: 0}
+	staged := make([]ImportStagingRow, 0, len(parsed))
+	for _, row := range parsed {
+		fingerprint := computeImportFingerprint(row.Date, row.Amount, row.Description, account)
+		status := classifyImportRow(uid, fingerprint, row.FITID, row.Date, row.Amount, row.Description)
+
+		stagingRow := ImportStagingRow{
+			ImportBatchID:   batch.ID,
+			Description:     row.Description,
+			Amount:          row.Amount,
+			TransactionDate: row.Date,
+			Fingerprint:     fingerprint,
+			FITID:           row.FITID,
+			Status:          status,
+			// Only a clean new row is auto-accepted; duplicates are dropped by
+			// default and conflicting rows need a second look (see
+			// classifyImportRow), so both require explicit opt-in via row_ids
+			// at commit time.
+			Accepted: status == importRowNew,
+		}
+		if err := DB.Create(&stagingRow).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal menyimpan baris staging import"})
+			return
+		}
+		summary[status]++
+		staged = append(staged, stagingRow)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"batch_id": batch.ID,
+		"summary":  summary,
+		"rows":     staged,
+	})
+}
+
+// CommitImportRequest is the optional body for POST
+// /api/v1/import/:batch_id/commit.
+type CommitImportRequest struct {
+	// RowIDs restricts the commit to specific staging rows, overriding each
+	// row's own Accepted flag. Omit to commit every row the preview accepted.
+	RowIDs       []uint `json:"row_ids"`
+	Recategorize bool   `json:"recategorize"`
+}
+
+// CommitImportBatch inserts the accepted staging rows of batch_id as real
+// Transactions and marks the batch committed, refusing to run twice on the
+// same batch. With recategorize=true it also runs the newly created
+// transactions through the existing AI categorizer (see
+// batchRecategorizeAI/BatchRecategorize).
+func CommitImportBatch(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	uid := userID.(uint)
+
+	var batch ImportBatch
+	if err := DB.Where("id = ? AND user_id = ?", c.Param("batch_id"), uid).First(&batch).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "batch_not_found", Message: "Batch import tidak ditemukan"})
+		return
+	}
+	if batch.Status == importBatchCommitted {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "already_committed", Message: "Batch import ini sudah di-commit"})
+		return
+	}
+
+	// The body is optional (both fields default to their zero value), so a
+	// bind failure other than an empty body is the only one worth rejecting.
+	var req CommitImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "validation_error", Message: err.Error()})
+		return
+	}
+
+	query := DB.Where("import_batch_id = ?", batch.ID)
+	if len(req.RowIDs) > 0 {
+		query = query.Where("id IN ?", req.RowIDs)
+	} else {
+		query = query.Where("accepted = ?", true)
+	}
+
+	var rows []ImportStagingRow
+	if err := query.Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal mengambil baris staging"})
+		return
+	}
+
+	var created []Transaction
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		for _, row := range rows {
+			txn := Transaction{
+				Description:     row.Description,
+				Amount:          row.Amount,
+				TransactionDate: row.TransactionDate,
+				UserID:          uid,
+				Currency:        "IDR",
+			}
+
+			knowledge, err := nextServerKnowledge(tx, uid)
+			if err != nil {
+				return err
+			}
+			txn.ServerKnowledge = knowledge
+
+			if err := tx.Create(&txn).Error; err != nil {
+				return err
+			}
+
+			// Imported transactions need the same balanced ledger legs a
+			// manually created Transaction gets, or they're invisible to
+			// GetTransactionSummary/GetMonthlyStats and /ledger/transactions.
+			if err := postTransactionEntries(tx, txn); err != nil {
+				return err
+			}
+
+			imported := ImportedTransaction{UserID: uid, Fingerprint: row.Fingerprint, FITID: row.FITID, TransactionID: txn.ID}
+			if err := tx.Create(&imported).Error; err != nil {
+				return err
+			}
+
+			created = append(created, txn)
+		}
+
+		now := time.Now()
+		batch.Status = importBatchCommitted
+		batch.CommittedAt = &now
+		return tx.Save(&batch).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal meng-commit batch import: " + err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"message":         "Batch import berhasil di-commit",
+		"committed_count": len(created),
+		"transactions":    created,
+	}
+
+	if req.Recategorize && len(created) > 0 {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), aiContextTimeout)
+		defer cancel()
+
+		updated, successCount, errorCount, err := batchRecategorizeAI(ctx, uid, created)
+		if err != nil {
+			response["recategorize_error"] = "Gagal melakukan kategorisasi AI: " + err.Error()
+		} else {
+			response["recategorized_count"] = successCount
+			response["recategorize_error_count"] = errorCount
+			response["recategorized_transactions"] = updated
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
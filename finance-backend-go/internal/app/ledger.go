@@ -0,0 +1,327 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// defaultAssetAccountPath is the implicit cash account every plain
+// Transaction (created outside the ledger endpoint) posts against.
+const defaultAssetAccountPath = "Assets:Default"
+
+// balanceEpsilon tolerates float rounding at Posting's decimal(14,2)
+// precision; anything beyond it is a genuinely unbalanced entry.
+const balanceEpsilon = 0.005
+
+// accountTypeByRoot maps an Account path's top-level segment (before the
+// first ":") to its AccountType, the same five categories as Formance/
+// luzifer-style ledgers.
+var accountTypeByRoot = map[string]AccountType{
+	"assets":      AccountTypeAsset,
+	"liabilities": AccountTypeLiability,
+	"equity":      AccountTypeEquity,
+	"income":      AccountTypeIncome,
+	"expenses":    AccountTypeExpense,
+}
+
+// accountTypeForPath infers an AccountType from path's root segment.
+func accountTypeForPath(path string) (AccountType, error) {
+	root, _, _ := strings.Cut(path, ":")
+	accountType, ok := accountTypeByRoot[strings.ToLower(root)]
+	if !ok {
+		return "", fmt.Errorf("akun %q tidak dikenali: harus diawali salah satu dari Assets, Liabilities, Equity, Income, Expenses", path)
+	}
+	return accountType, nil
+}
+
+// findOrCreateAccount resolves userID's account at path, creating it
+// (auto-vivified, the same way findOrCreateCategory treats categories) if it
+// doesn't exist yet. Pass a *gorm.DB transaction so callers that need this
+// alongside a Posting insert stay atomic.
+func findOrCreateAccount(tx *gorm.DB, userID uint, path string) (Account, error) {
+	accountType, err := accountTypeForPath(path)
+	if err != nil {
+		return Account{}, err
+	}
+
+	var account Account
+	err = tx.Where("user_id = ? AND path = ?", userID, path).First(&account).Error
+	if err == nil {
+		return account, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return Account{}, err
+	}
+
+	account = Account{UserID: userID, Path: path, Type: accountType}
+	if err := tx.Create(&account).Error; err != nil {
+		return Account{}, err
+	}
+	return account, nil
+}
+
+// categoryAccountPath maps a transaction's category name to a leaf account
+// under root ("Income" or "Expenses"), falling back to "Uncategorized" when
+// the transaction has no category.
+func categoryAccountPath(root, categoryName string) string {
+	if categoryName == "" {
+		categoryName = "Uncategorized"
+	}
+	return root + ":" + categoryName
+}
+
+// postTransactionEntries writes the balanced two-posting entry backing txn's
+// signed Amount: a leg against Assets:Default and the offsetting leg against
+// an Income/Expenses account named after txn's category. It's a no-op if txn
+// already has postings, so BackfillLedgerPostings can call it unconditionally.
+func postTransactionEntries(tx *gorm.DB, txn Transaction) error {
+	var existing int64
+	if err := tx.Model(&Posting{}).Where("transaction_id = ?", txn.ID).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	categoryName := ""
+	if txn.CategoryID != nil {
+		var category Category
+		if err := tx.First(&category, *txn.CategoryID).Error; err == nil {
+			categoryName = category.Name
+		}
+	}
+
+	assetAccount, err := findOrCreateAccount(tx, txn.UserID, defaultAssetAccountPath)
+	if err != nil {
+		return err
+	}
+
+	// Expenses/Income increase with a debit (positive amount here); the
+	// offsetting Assets:Default leg carries txn.Amount's original sign so the
+	// two legs sum to zero.
+	root := "Income"
+	if txn.Amount < 0 {
+		root = "Expenses"
+	}
+	categoryAccount, err := findOrCreateAccount(tx, txn.UserID, categoryAccountPath(root, categoryName))
+	if err != nil {
+		return err
+	}
+
+	currency := txn.Currency
+	if currency == "" {
+		currency = "IDR"
+	}
+
+	postings := []Posting{
+		{TransactionID: txn.ID, AccountID: assetAccount.ID, Amount: txn.Amount, Currency: currency},
+		{TransactionID: txn.ID, AccountID: categoryAccount.ID, Amount: -txn.Amount, Currency: currency},
+	}
+	return tx.Create(&postings).Error
+}
+
+// hasSimpleLedgerPostings reports whether transactionID's existing Postings
+// are either absent or the plain two-leg Assets:Default/category structure
+// postTransactionEntries itself produces. A ledger-originated entry posted
+// via CreateLedgerTransaction can have any number of legs across arbitrary
+// accounts, and rewriting those into a generic 2-leg shape would silently
+// destroy their real structure — callers use this to decide whether it's
+// safe to drop and recreate postings after an edit.
+func hasSimpleLedgerPostings(tx *gorm.DB, transactionID uint) (bool, error) {
+	var postings []Posting
+	if err := tx.Where("transaction_id = ?", transactionID).Find(&postings).Error; err != nil {
+		return false, err
+	}
+	if len(postings) == 0 {
+		return true, nil
+	}
+	if len(postings) != 2 {
+		return false, nil
+	}
+
+	for _, p := range postings {
+		var account Account
+		if err := tx.First(&account, p.AccountID).Error; err != nil {
+			return false, err
+		}
+		if account.Path == defaultAssetAccountPath {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// BackfillLedgerPostings converts every Transaction row that predates the
+// ledger (i.e. has no Postings yet) into a balanced two-posting entry against
+// Assets:Default. It's the migration path for `migrate backfill-ledger`, and
+// safe to run repeatedly since postTransactionEntries skips already-posted
+// transactions.
+func BackfillLedgerPostings() error {
+	var transactions []Transaction
+	if err := DB.Where("id NOT IN (SELECT DISTINCT transaction_id FROM postings)").Find(&transactions).Error; err != nil {
+		return fmt.Errorf("failed to load unposted transactions: %w", err)
+	}
+
+	log.Printf("Backfilling ledger postings for %d transaction(s)...", len(transactions))
+
+	backfilled := 0
+	for _, txn := range transactions {
+		if err := postTransactionEntries(DB, txn); err != nil {
+			log.Printf("Failed to backfill postings for transaction %d: %v", txn.ID, err)
+			continue
+		}
+		backfilled++
+	}
+
+	log.Printf("Ledger backfill complete: %d/%d transaction(s) posted.", backfilled, len(transactions))
+	return nil
+}
+
+// LedgerPostingInput is one leg of a POST /ledger/transactions request.
+type LedgerPostingInput struct {
+	AccountPath string  `json:"account_path" binding:"required"`
+	Amount      float64 `json:"amount" binding:"required"`
+	Currency    string  `json:"currency"`
+}
+
+// LedgerTransactionRequest is the body for POST /ledger/transactions: a
+// transaction header plus its full set of postings, which must balance to
+// zero per currency.
+type LedgerTransactionRequest struct {
+	Description     string               `json:"description" binding:"required,max=255"`
+	TransactionDate *time.Time           `json:"transaction_date"`
+	Postings        []LedgerPostingInput `json:"postings" binding:"required,min=2,dive"`
+}
+
+// validatePostingsBalance rejects an entry whose postings don't sum to zero
+// within balanceEpsilon, grouped by currency (a multi-currency entry balances
+// each currency independently; see chunk2-2 for FX conversion between them).
+func validatePostingsBalance(postings []LedgerPostingInput) error {
+	sums := make(map[string]float64)
+	for _, p := range postings {
+		currency := p.Currency
+		if currency == "" {
+			currency = "IDR"
+		}
+		sums[currency] += p.Amount
+	}
+
+	for currency, sum := range sums {
+		if math.Abs(sum) > balanceEpsilon {
+			return fmt.Errorf("posting %s tidak balance: total %.2f, harus 0", currency, sum)
+		}
+	}
+	return nil
+}
+
+// CreateLedgerTransaction creates a Transaction header and its full set of
+// Postings atomically, rejecting the entry if the postings don't balance.
+// Unlike CreateTransaction, the caller decides every account directly rather
+// than going through AI categorization.
+func CreateLedgerTransaction(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+	uid := userID.(uint)
+
+	var req LedgerTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := validatePostingsBalance(req.Postings); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "unbalanced_entry",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	transactionDate := time.Now()
+	if req.TransactionDate != nil {
+		transactionDate = *req.TransactionDate
+	}
+
+	// The header's Amount mirrors the entry's net effect on asset accounts,
+	// so amount-based readers that haven't moved to postings yet (export,
+	// budgets) still see a sensible number for a ledger-created entry.
+	var headerAmount float64
+	for _, p := range req.Postings {
+		if accountType, err := accountTypeForPath(p.AccountPath); err == nil && accountType == AccountTypeAsset {
+			headerAmount += p.Amount
+		}
+	}
+
+	transaction := Transaction{
+		Description:     req.Description,
+		Amount:          headerAmount,
+		TransactionDate: transactionDate,
+		UserID:          uid,
+	}
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&transaction).Error; err != nil {
+			return err
+		}
+
+		for _, p := range req.Postings {
+			account, err := findOrCreateAccount(tx, uid, p.AccountPath)
+			if err != nil {
+				return err
+			}
+
+			currency := p.Currency
+			if currency == "" {
+				currency = "IDR"
+			}
+
+			posting := Posting{
+				TransactionID: transaction.ID,
+				AccountID:     account.ID,
+				Amount:        p.Amount,
+				Currency:      currency,
+			}
+			if err := tx.Create(&posting).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal menyimpan entri ledger: " + err.Error(),
+		})
+		return
+	}
+
+	if err := DB.Preload("Postings.Account").First(&transaction, transaction.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Entri ledger tersimpan tapi gagal dimuat ulang",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Entri ledger berhasil disimpan",
+		"transaction": transaction,
+	})
+}
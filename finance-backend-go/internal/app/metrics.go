@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Prometheus metrics so operators can graph categorization quality over time.
+var (
+	aiPredictionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "finance_ai_predictions_total",
+		Help: "Total number of transaction category predictions, labeled by method and resulting category.",
+	}, []string{"method", "category"})
+
+	aiConfidenceBucket = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "finance_ai_confidence_bucket",
+		Help:    "Distribution of AI category prediction confidence scores.",
+		Buckets: []float64{0.1, 0.25, 0.5, 0.6, 0.7, 0.8, 0.9, 0.95, 1.0},
+	})
+
+	aiServiceUp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "finance_ai_service_up",
+		Help: "Whether the AI categorization service is reachable (1) or not (0).",
+	})
+)
+
+// recordPrediction records a single categorization outcome against the
+// Prometheus metrics above. Call this from every write path that assigns a
+// prediction_method/category to a transaction.
+func recordPrediction(method, category string, confidence float64) {
+	aiPredictionsTotal.WithLabelValues(method, category).Inc()
+	aiConfidenceBucket.Observe(confidence)
+}
+
+// startAIServiceHealthGauge periodically polls CheckAIServiceHealth and keeps
+// finance_ai_service_up in sync.
+func startAIServiceHealthGauge(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	updateAIServiceUpGauge()
+	for range ticker.C {
+		updateAIServiceUpGauge()
+	}
+}
+
+func updateAIServiceUpGauge() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := CheckAIServiceHealth(ctx); err != nil {
+		aiServiceUp.Set(0)
+	} else {
+		aiServiceUp.Set(1)
+	}
+}
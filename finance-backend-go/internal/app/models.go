@@ -0,0 +1,461 @@
+package app
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// Role is a user's access level. It replaces the old IsAdmin bool so a third
+// tier (e.g. a read-only auditor role) doesn't need another boolean column,
+// the way Supabase names its elevated service role supabase_admin rather
+// than a flag.
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+// User represents a user in the system
+type User struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	Username  string         `gorm:"uniqueIndex;not null;size:100" json:"username"`
+	Password  string         `gorm:"not null;size:255" json:"-"` // "-" tag untuk tidak menampilkan password di JSON
+	Role      Role           `gorm:"not null;size:20;default:'user'" json:"role"`
+	// BaseCurrency is the ISO-4217 code GetTransactionSummary/GetMonthlyStats
+	// convert every transaction into, regardless of the currency it was
+	// recorded in (see fx.go).
+	BaseCurrency string `gorm:"not null;size:3;default:'IDR'" json:"base_currency"`
+	// ServerKnowledge is this user's delta-sync counter, bumped by
+	// nextServerKnowledge every time one of their transactions or categories
+	// is created, updated, or deleted (see sync.go). It's YNAB's
+	// last_knowledge_of_server pattern, scoped per user instead of per budget.
+	ServerKnowledge uint64 `gorm:"not null;default:0" json:"-"`
+	// RolloverOverspend controls what computeCarryover does when a rolled-over
+	// Budget's Available for a month went negative: false (default) clamps the
+	// carryover to 0 so overspending doesn't eat into next month; true lets it
+	// carry the deficit forward, reducing next month's Available. See envelope.go.
+	RolloverOverspend bool `gorm:"not null;default:false" json:"rollover_overspend"`
+
+	// Relasi: User has many Categories
+	Categories []Category `gorm:"foreignKey:UserID" json:"categories,omitempty"`
+
+	// Relasi: User has many Transactions
+	Transactions []Transaction `gorm:"foreignKey:UserID" json:"transactions,omitempty"`
+}
+
+// Category represents a transaction category
+type Category struct {
+	ID           uint           `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	Name         string         `gorm:"not null;size:100" json:"name"`
+	UserID       *uint          `gorm:"index" json:"user_id"` // Nil means a global category managed by an admin
+	Budget       float64        `gorm:"not null;default:0" json:"budget"`
+	BudgetPeriod string         `gorm:"size:20;default:'monthly'" json:"budget_period"` // "monthly", "weekly", or "yearly"
+	// ServerKnowledge is the value of the owning user's counter as of this
+	// row's last write; GetSync uses it to find rows changed since a client's
+	// since_knowledge cursor. See sync.go.
+	ServerKnowledge uint64 `gorm:"not null;default:0;index" json:"server_knowledge"`
+
+	// Relasi: Category belongs to User
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+
+	// Relasi: Category has many Transactions
+	Transactions []Transaction `gorm:"foreignKey:CategoryID" json:"transactions,omitempty"`
+}
+
+// Transaction represents a financial transaction
+type Transaction struct {
+	ID               uint           `gorm:"primarykey" json:"id"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	Description      string         `gorm:"not null;size:255" json:"description"`
+	Amount           float64        `gorm:"not null;type:decimal(10,2)" json:"amount"`
+	TransactionDate  time.Time      `gorm:"not null" json:"transaction_date"`
+	UserID           uint           `gorm:"not null;index" json:"user_id"`
+	CategoryID       *uint          `gorm:"index" json:"category_id"` // Nullable, bisa tidak ada kategori
+	PredictionMethod string         `gorm:"size:50;index" json:"prediction_method,omitempty"`
+	AIConfidence     float64        `gorm:"not null;default:0" json:"ai_confidence"`
+	Currency         string         `gorm:"not null;size:3;default:'IDR'" json:"currency"`
+	// ServerKnowledge is the value of the owning user's counter as of this
+	// row's last write; GetSync uses it to find rows changed since a client's
+	// since_knowledge cursor. See sync.go.
+	ServerKnowledge uint64 `gorm:"not null;default:0;index" json:"server_knowledge"`
+
+	// Relasi: Transaction belongs to User
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+
+	// Relasi: Transaction belongs to Category (optional)
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+
+	// Relasi: Transaction has many Postings (the double-entry ledger legs
+	// backing this Amount; see ledger.go). Older rows only get these once
+	// `migrate backfill-ledger` has run.
+	Postings []Posting `gorm:"foreignKey:TransactionID" json:"postings,omitempty"`
+}
+
+// AccountType classifies an Account per standard double-entry bookkeeping
+// categories. GetTransactionSummary/GetMonthlyStats group postings by this
+// instead of by the sign of Transaction.Amount.
+type AccountType string
+
+const (
+	AccountTypeAsset     AccountType = "asset"
+	AccountTypeLiability AccountType = "liability"
+	AccountTypeEquity    AccountType = "equity"
+	AccountTypeIncome    AccountType = "income"
+	AccountTypeExpense   AccountType = "expense"
+)
+
+// Account is a node in a user's chart of accounts, addressed by a
+// colon-separated hierarchical path (e.g. "Assets:Cash", "Expenses:Food"),
+// the way ledger/hledger-style plaintext accounting tools do. See
+// findOrCreateAccount for how the path resolves to a Type.
+type Account struct {
+	ID        uint        `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	UserID    uint        `gorm:"not null;uniqueIndex:idx_account_user_path" json:"user_id"`
+	Path      string      `gorm:"not null;size:255;uniqueIndex:idx_account_user_path" json:"path"`
+	Type      AccountType `gorm:"not null;size:20" json:"type"`
+
+	// Relasi: Account belongs to User
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+func (Account) TableName() string {
+	return "accounts"
+}
+
+// Posting is one leg of a double-entry Transaction. A balanced Transaction
+// has at least 2 postings whose Amount sums to zero per Currency; see
+// validatePostingsBalance.
+type Posting struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	TransactionID uint      `gorm:"not null;index" json:"transaction_id"`
+	AccountID     uint      `gorm:"not null;index" json:"account_id"`
+	Amount        float64   `gorm:"not null;type:decimal(14,2)" json:"amount"`
+	Currency      string    `gorm:"not null;size:3;default:'IDR'" json:"currency"`
+
+	// Relasi: Posting belongs to Account
+	Account Account `gorm:"foreignKey:AccountID" json:"account,omitempty"`
+}
+
+func (Posting) TableName() string {
+	return "postings"
+}
+
+// YNABAccount stores a user's linked You Need A Budget connection so scheduled
+// syncs know which budget to pull and where to resume from.
+type YNABAccount struct {
+	ID                    uint           `gorm:"primarykey" json:"id"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	UserID                uint           `gorm:"not null;uniqueIndex;index" json:"user_id"`
+	AccessToken           string         `gorm:"not null;size:255" json:"-"`
+	BudgetID              string         `gorm:"not null;size:100" json:"budget_id"`
+	LastKnowledgeOfServer int64          `gorm:"not null;default:0" json:"last_knowledge_of_server"`
+	AutoSync              bool           `gorm:"not null;default:false" json:"auto_sync"`
+	LastSyncedAt          *time.Time     `json:"last_synced_at"`
+
+	// Relasi: YNABAccount belongs to User
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName methods untuk custom table names (opsional)
+func (User) TableName() string {
+	return "users"
+}
+
+func (Category) TableName() string {
+	return "categories"
+}
+
+func (Transaction) TableName() string {
+	return "transactions"
+}
+
+func (YNABAccount) TableName() string {
+	return "ynab_accounts"
+}
+
+// IdempotencyRecord caches the response of a previously handled request so
+// retries with the same Idempotency-Key don't repeat side effects such as
+// category creation. See IdempotencyMiddleware.
+type IdempotencyRecord struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	UserID         uint      `gorm:"not null;uniqueIndex:idx_idempotency_user_key" json:"user_id"`
+	IdempotencyKey string    `gorm:"not null;size:255;uniqueIndex:idx_idempotency_user_key" json:"idempotency_key"`
+	RequestHash    string    `gorm:"not null;size:64" json:"-"`
+	InFlight       bool      `gorm:"not null;default:true" json:"-"`
+	StatusCode     int       `json:"-"`
+	ResponseBody   string    `gorm:"type:text" json:"-"`
+	ExpiresAt      time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
+func (IdempotencyRecord) TableName() string {
+	return "idempotency_records"
+}
+
+// BudgetAlert records a category crossing a budget threshold (80%/100%)
+// within a given period, so the nightly job doesn't re-alert every run.
+type BudgetAlert struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	CategoryID  uint      `gorm:"not null;uniqueIndex:idx_budget_alert_period" json:"category_id"`
+	PeriodStart time.Time `gorm:"not null;uniqueIndex:idx_budget_alert_period" json:"period_start"`
+	Threshold   int       `gorm:"not null;uniqueIndex:idx_budget_alert_period" json:"threshold"` // 80 or 100
+	PercentUsed float64   `gorm:"not null" json:"percent_used"`
+
+	Category Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
+
+func (BudgetAlert) TableName() string {
+	return "budget_alerts"
+}
+
+// Session is a refresh-token-backed login session. Access JWTs embed the
+// session ID so revoking a session (logout, reuse detection) invalidates the
+// access token early even though the JWT itself is still unexpired.
+type Session struct {
+	ID               uint       `gorm:"primarykey" json:"id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UserID           uint       `gorm:"not null;index" json:"user_id"`
+	RefreshTokenHash string     `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	UserAgent        string     `gorm:"size:255" json:"user_agent"`
+	IP               string     `gorm:"size:64" json:"ip"`
+	ExpiresAt        time.Time  `gorm:"not null;index" json:"expires_at"`
+	RevokedAt        *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+	ReplacedBy       *uint      `json:"replaced_by,omitempty"`
+
+	// Relasi: Session belongs to User
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// ExchangeRate is one day's FromCurrency->ToCurrency rate, populated either
+// by an admin via POST /api/v1/fx/rates or by RefreshExchangeRates pulling
+// from a pluggable FXRateFetcher (see fx.go). lookupExchangeRate reads these
+// to convert transactions into a user's BaseCurrency.
+type ExchangeRate struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Date         time.Time `gorm:"not null;uniqueIndex:idx_fx_rate_day" json:"date"`
+	FromCurrency string    `gorm:"not null;size:3;uniqueIndex:idx_fx_rate_day" json:"from_currency"`
+	ToCurrency   string    `gorm:"not null;size:3;uniqueIndex:idx_fx_rate_day" json:"to_currency"`
+	Rate         float64   `gorm:"not null;type:decimal(18,8)" json:"rate"`
+}
+
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
+
+// RecurringTransaction is a template plus an RRULE-like schedule that
+// recurringSchedulerTick materializes into real Transaction rows. The
+// schedule fields (Frequency/Interval/ByDay/ByMonthday/DTStart/Until/Count)
+// mirror the subset of RFC 5545 RRULE keys named in the request: FREQ,
+// INTERVAL, BYDAY, BYMONTHDAY, DTSTART, UNTIL, COUNT. See recurring.go.
+type RecurringTransaction struct {
+	ID          uint           `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	UserID      uint           `gorm:"not null;index" json:"user_id"`
+	Description string         `gorm:"not null;size:255" json:"description"`
+	Amount      float64        `gorm:"not null;type:decimal(10,2)" json:"amount"`
+	Currency    string         `gorm:"not null;size:3;default:'IDR'" json:"currency"`
+	CategoryID  *uint          `gorm:"index" json:"category_id"`
+
+	// Frequency is one of "DAILY", "WEEKLY", "MONTHLY" (RRULE FREQ).
+	Frequency string `gorm:"not null;size:10" json:"frequency"`
+	// Interval is RRULE INTERVAL: materialize every Interval periods instead
+	// of every one. Zero is treated as 1.
+	Interval int `gorm:"not null;default:1" json:"interval"`
+	// ByDay is RRULE BYDAY: a comma-separated list of MO/TU/WE/TH/FR/SA/SU,
+	// only consulted for Frequency="WEEKLY" with Interval=1.
+	ByDay string `gorm:"size:50" json:"byday,omitempty"`
+	// ByMonthday is RRULE BYMONTHDAY: the day of month to recur on, only
+	// consulted for Frequency="MONTHLY". Zero falls back to DTStart's day.
+	ByMonthday int       `gorm:"default:0" json:"by_month_day,omitempty"`
+	DTStart    time.Time `gorm:"not null" json:"dtstart"`
+	// Until is RRULE UNTIL: stop materializing once an occurrence would fall
+	// after this date. Nil means no end date.
+	Until *time.Time `json:"until,omitempty"`
+	// Count is RRULE COUNT: stop after this many occurrences. Nil means
+	// unbounded (subject only to Until).
+	Count *int `json:"count,omitempty"`
+	// OccurrenceCount is how many occurrences have been materialized so far,
+	// compared against Count to know when to stop.
+	OccurrenceCount int `gorm:"not null;default:0" json:"occurrence_count"`
+	// NextRunAt is the next occurrence date due to be materialized;
+	// recurringSchedulerTick selects rows where this has passed.
+	NextRunAt time.Time `gorm:"not null;index" json:"next_run_at"`
+	// Active is cleared once Until/Count is reached, or the user pauses it.
+	Active bool `gorm:"not null;default:true;index" json:"active"`
+
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
+
+func (RecurringTransaction) TableName() string {
+	return "recurring_transactions"
+}
+
+// RecurringOccurrence records one materialized occurrence of a
+// RecurringTransaction, keyed by (RecurringID, OccurrenceDate) so a tick that
+// runs twice for the same due date (e.g. after a crash/restart) doesn't
+// double-post the Transaction it creates.
+type RecurringOccurrence struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	RecurringID    uint      `gorm:"not null;uniqueIndex:idx_recurring_occurrence" json:"recurring_id"`
+	OccurrenceDate time.Time `gorm:"not null;uniqueIndex:idx_recurring_occurrence" json:"occurrence_date"`
+	TransactionID  uint      `gorm:"not null" json:"transaction_id"`
+}
+
+func (RecurringOccurrence) TableName() string {
+	return "recurring_occurrences"
+}
+
+// ImportProfile is a named CSV column mapping saved so a user doesn't have
+// to resend date_column/amount_column/description_column on every bank
+// export upload. Only CSV needs this — OFX and QIF have a fixed field
+// layout. See import.go.
+type ImportProfile struct {
+	ID                uint      `gorm:"primarykey" json:"id"`
+	CreatedAt         time.Time `json:"created_at"`
+	UserID            uint      `gorm:"not null;index" json:"user_id"`
+	Name              string    `gorm:"not null;size:100" json:"name"`
+	DateColumn        string    `gorm:"not null;size:100" json:"date_column"`
+	AmountColumn      string    `gorm:"not null;size:100" json:"amount_column"`
+	DescriptionColumn string    `gorm:"not null;size:100" json:"description_column"`
+	// DateFormat is a Go reference-time layout (default "2006-01-02").
+	DateFormat string `gorm:"size:50" json:"date_format,omitempty"`
+}
+
+func (ImportProfile) TableName() string {
+	return "import_profiles"
+}
+
+// ImportBatch is one POST /api/v1/import upload, staged as
+// ImportStagingRow rows for review before POST
+// /api/v1/import/:batch_id/commit turns the accepted ones into real
+// Transactions.
+type ImportBatch struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	Format      string     `gorm:"not null;size:10" json:"format"` // ofx, qif, or csv
+	Account     string     `gorm:"size:255" json:"account"`
+	Status      string     `gorm:"not null;size:20;default:'staged'" json:"status"` // staged or committed
+	CommittedAt *time.Time `json:"committed_at,omitempty"`
+}
+
+func (ImportBatch) TableName() string {
+	return "import_batches"
+}
+
+// ImportStagingRow is one parsed row from an ImportBatch upload, not yet a
+// Transaction until its batch is committed. Status/Accepted record the
+// duplicate-detection preview shown to the user before commit.
+type ImportStagingRow struct {
+	ID              uint      `gorm:"primarykey" json:"id"`
+	ImportBatchID   uint      `gorm:"not null;index" json:"import_batch_id"`
+	Description     string    `gorm:"not null;size:255" json:"description"`
+	Amount          float64   `gorm:"not null;type:decimal(10,2)" json:"amount"`
+	TransactionDate time.Time `gorm:"not null" json:"transaction_date"`
+	// Fingerprint is a hash of (date, amount, normalized description,
+	// account); see computeImportFingerprint.
+	Fingerprint string `gorm:"not null;size:64;index" json:"fingerprint"`
+	// FITID is OFX's own unique transaction ID, empty for QIF/CSV rows.
+	FITID string `gorm:"size:255" json:"fitid,omitempty"`
+	// Status is "new", "duplicate", or "conflicting" (see classifyImportRow).
+	Status string `gorm:"not null;size:20" json:"status"`
+	// Accepted is whether this row will be committed; defaults to true
+	// except for exact duplicates, and can be overridden per-row at commit
+	// time via CommitImportRequest.RowIDs.
+	Accepted bool `gorm:"not null;default:true" json:"accepted"`
+}
+
+func (ImportStagingRow) TableName() string {
+	return "import_staging_rows"
+}
+
+// ImportedTransaction records a committed ImportStagingRow's fingerprint
+// (and FITID, for OFX) against the Transaction it became, so future imports
+// can dedupe against it the same way they dedupe against rows already in
+// Transaction. See classifyImportRow.
+type ImportedTransaction struct {
+	ID            uint      `gorm:"primarykey" json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UserID        uint      `gorm:"not null;index" json:"user_id"`
+	Fingerprint   string    `gorm:"not null;size:64;index" json:"fingerprint"`
+	FITID         string    `gorm:"size:255;index" json:"fitid,omitempty"`
+	TransactionID uint      `gorm:"not null" json:"transaction_id"`
+}
+
+func (ImportedTransaction) TableName() string {
+	return "imported_transactions"
+}
+
+// Budget is one category's envelope assignment for one calendar month (the
+// YNAB "assign a dollar to every job" model), distinct from Category's own
+// simple Budget/BudgetPeriod fields (see budget.go) which track a single
+// recurring spend limit rather than a month-by-month plan. See envelope.go.
+type Budget struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_budget_user_category_month" json:"user_id"`
+	CategoryID uint      `gorm:"not null;uniqueIndex:idx_budget_user_category_month" json:"category_id"`
+	// Month is "YYYY-MM".
+	Month          string  `gorm:"not null;size:7;uniqueIndex:idx_budget_user_category_month" json:"month"`
+	AssignedAmount float64 `gorm:"not null;default:0;type:decimal(10,2)" json:"assigned_amount"`
+	// Rollover carries a positive Available balance (and, if the user's
+	// RolloverOverspend setting is on, a negative one) into next month's
+	// carryover. See computeCarryover.
+	Rollover bool `gorm:"not null;default:false" json:"rollover"`
+
+	Category Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
+
+func (Budget) TableName() string {
+	return "budgets"
+}
+
+// SyncEntityType names the kind of row a SyncTombstone stands in for.
+type SyncEntityType string
+
+const (
+	SyncEntityTransaction SyncEntityType = "transaction"
+	SyncEntityCategory    SyncEntityType = "category"
+)
+
+// SyncTombstone records a hard delete for GetSync: once a row is gone, its
+// own ServerKnowledge can't be queried anymore, so a tombstone is the only
+// way a client polling since_knowledge learns to drop it locally. See
+// sync.go.
+type SyncTombstone struct {
+	ID              uint           `gorm:"primarykey" json:"id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UserID          uint           `gorm:"not null;index" json:"user_id"`
+	EntityType      SyncEntityType `gorm:"not null;size:20" json:"entity_type"`
+	EntityID        uint           `gorm:"not null" json:"entity_id"`
+	ServerKnowledge uint64         `gorm:"not null;index" json:"server_knowledge"`
+}
+
+func (SyncTombstone) TableName() string {
+	return "sync_tombstones"
+}
@@ -0,0 +1,202 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// stepUpAAL is the AAL (Authenticator Assurance Level) claim embedded in a
+// token minted by Reauthenticate, borrowing the aal2 name from Supabase Auth.
+// The app only ever issues aal1 (the default, empty AAL) or aal2 tokens; a
+// third factor would introduce aal3 but there's no such factor here yet.
+const stepUpAAL = "aal2"
+
+// reauthMaxAge is how long a step-up token stays valid for RequireReauth
+// routes, mirroring accessTokenTTL: short enough that a stolen access token
+// can't be replayed against a destructive endpoint long after the real user
+// stepped away.
+const reauthMaxAge = 5 * time.Minute
+
+// ReauthenticateRequest carries the current password and, for accounts with
+// TOTP enrolled, a TOTP code. Neither this codebase nor the User model has
+// TOTP enrollment yet, so TOTPCode is accepted but ignored until that lands;
+// password verification alone gates the step-up token for now.
+type ReauthenticateRequest struct {
+	Password string `json:"password" binding:"required"`
+	TOTPCode string `json:"totp_code"`
+}
+
+// Reauthenticate re-verifies the caller's password and, on success, issues a
+// fresh access token stamped with a ReauthAt timestamp and an aal2 claim.
+// RequireReauth checks that timestamp, so this is how a client proves "the
+// user is at the keyboard right now" before a destructive operation, even
+// though their existing access token may be minutes old.
+func Reauthenticate(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+
+	var req ReauthenticateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var user User
+	if err := DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User tidak ditemukan",
+		})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "invalid_credentials",
+			Message: "Password salah",
+		})
+		return
+	}
+
+	sessionID, _ := c.Get("session_id")
+
+	token, err := generateStepUpJWT(user.ID, user.Username, user.Role, sessionID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "token_error",
+			Message: "Gagal membuat token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":   token,
+		"message": "Re-autentikasi berhasil",
+	})
+}
+
+// generateStepUpJWT mints the same short-lived access token generateJWT does,
+// plus the AAL/ReauthAt claims RequireReauth looks for.
+func generateStepUpJWT(userID uint, username string, role Role, sessionID uint) (string, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:    userID,
+		Username:  username,
+		SessionID: sessionID,
+		Role:      role,
+		AAL:       stepUpAAL,
+		ReauthAt:  now.Unix(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "finance-backend-go",
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret)
+}
+
+// RequireReauth guards a handler behind a recently-issued step-up token,
+// rejecting the request unless the caller's token carries a ReauthAt claim
+// no older than maxAge. Route it after JWTMiddleware, which is what
+// populates the "reauth_at" context value this reads.
+//
+// This defends against a stolen (but still-valid) access token being used
+// for a destructive action: the thief also needs the password, minted within
+// the last maxAge, to pass this check.
+func RequireReauth(maxAge time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reauthAt, exists := c.Get("reauth_at")
+		if !exists || reauthAt.(int64) == 0 {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "reauth_required",
+				Message: "Aksi ini memerlukan re-autentikasi, silakan verifikasi ulang password Anda via /auth/reauthenticate",
+			})
+			c.Abort()
+			return
+		}
+
+		if time.Since(time.Unix(reauthAt.(int64), 0)) > maxAge {
+			c.JSON(http.StatusForbidden, ErrorResponse{
+				Error:   "reauth_expired",
+				Message: "Re-autentikasi Anda sudah kadaluarsa, silakan verifikasi ulang password Anda via /auth/reauthenticate",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ChangePasswordRequest is the body for POST /auth/password, guarded by
+// RequireReauth so a stolen access token alone can't lock the real owner out.
+type ChangePasswordRequest struct {
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// ChangePassword updates the caller's password. It's registered behind
+// RequireReauth in SetupRoutes.
+func ChangePassword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "hash_error",
+			Message: "Gagal melakukan hash password",
+		})
+		return
+	}
+
+	if err := DB.Model(&User{}).Where("id = ?", userID).Update("password", string(hashed)).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengupdate password",
+		})
+		return
+	}
+
+	// Rotating credentials makes every other session's refresh token suspect;
+	// the caller's own session survives so they aren't logged out by the
+	// change they just made.
+	var currentSessionID uint
+	if sessionID, exists := c.Get("session_id"); exists {
+		currentSessionID = sessionID.(uint)
+	}
+	revokeSessionChain(userID.(uint), currentSessionID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password berhasil diubah, silakan login kembali di perangkat lain",
+	})
+}
@@ -0,0 +1,494 @@
+package app
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var validRecurringFrequencies = map[string]bool{"DAILY": true, "WEEKLY": true, "MONTHLY": true}
+
+var weekdayByAbbrev = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// CreateRecurringTransactionRequest is the body for POST /api/v1/recurring.
+type CreateRecurringTransactionRequest struct {
+	Description string     `json:"description" binding:"required,max=255"`
+	Amount      float64    `json:"amount" binding:"required"`
+	Currency    string     `json:"currency"`
+	CategoryID  *uint      `json:"category_id"`
+	Frequency   string     `json:"frequency" binding:"required"`
+	Interval    int        `json:"interval"`
+	ByDay       string     `json:"byday"`
+	ByMonthday  int        `json:"by_month_day"`
+	DTStart     time.Time  `json:"dtstart" binding:"required"`
+	Until       *time.Time `json:"until"`
+	Count       *int       `json:"count"`
+}
+
+// validateRecurringSchedule checks the RRULE-subset fields common to create
+// and update requests.
+func validateRecurringSchedule(frequency string, interval int, count *int) error {
+	if !validRecurringFrequencies[frequency] {
+		return errInvalidFrequency
+	}
+	if interval < 0 {
+		return errInvalidInterval
+	}
+	if count != nil && *count <= 0 {
+		return errInvalidCount
+	}
+	return nil
+}
+
+var (
+	errInvalidFrequency = &fieldError{"frequency harus salah satu dari: DAILY, WEEKLY, MONTHLY"}
+	errInvalidInterval  = &fieldError{"interval tidak boleh negatif"}
+	errInvalidCount     = &fieldError{"count harus lebih besar dari 0"}
+)
+
+// fieldError is a minimal error type for the validation messages above; it
+// exists so they can be declared as package-level vars instead of
+// constructed with fmt.Errorf at each call site.
+type fieldError struct{ message string }
+
+func (e *fieldError) Error() string { return e.message }
+
+// CreateRecurringTransaction creates a recurring transaction template owned
+// by the caller. NextRunAt starts at DTStart, so the first materialization
+// happens on or after that date once the scheduler ticks.
+func CreateRecurringTransaction(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	uid := userID.(uint)
+
+	var req CreateRecurringTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "validation_error", Message: err.Error()})
+		return
+	}
+
+	if err := validateRecurringSchedule(req.Frequency, req.Interval, req.Count); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_schedule", Message: err.Error()})
+		return
+	}
+
+	interval := req.Interval
+	if interval == 0 {
+		interval = 1
+	}
+	currency := req.Currency
+	if currency == "" {
+		currency = "IDR"
+	}
+
+	recurring := RecurringTransaction{
+		UserID:      uid,
+		Description: req.Description,
+		Amount:      req.Amount,
+		Currency:    currency,
+		CategoryID:  req.CategoryID,
+		Frequency:   req.Frequency,
+		Interval:    interval,
+		ByDay:       req.ByDay,
+		ByMonthday:  req.ByMonthday,
+		DTStart:     req.DTStart,
+		Until:       req.Until,
+		Count:       req.Count,
+		NextRunAt:   req.DTStart,
+		Active:      true,
+	}
+
+	if err := DB.Create(&recurring).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal membuat transaksi berulang"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Transaksi berulang berhasil dibuat",
+		"recurring": recurring,
+	})
+}
+
+// GetRecurringTransactions lists the caller's recurring transaction
+// templates.
+func GetRecurringTransactions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var recurring []RecurringTransaction
+	if err := DB.Where("user_id = ?", userID).Find(&recurring).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal mengambil transaksi berulang"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recurring": recurring})
+}
+
+// findOwnedRecurring loads a RecurringTransaction the caller owns, or writes
+// a 404 and returns ok=false.
+func findOwnedRecurring(c *gin.Context, userID interface{}) (RecurringTransaction, bool) {
+	var recurring RecurringTransaction
+	if err := DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&recurring).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "recurring_not_found", Message: "Transaksi berulang tidak ditemukan"})
+		return RecurringTransaction{}, false
+	}
+	return recurring, true
+}
+
+// GetRecurringTransactionByID returns a single recurring transaction owned
+// by the caller.
+func GetRecurringTransactionByID(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	recurring, ok := findOwnedRecurring(c, userID)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"recurring": recurring})
+}
+
+// UpdateRecurringTransaction updates a recurring transaction's template and
+// schedule fields. Changing the schedule re-derives NextRunAt from the new
+// DTStart rather than trying to reconcile it with the old schedule.
+func UpdateRecurringTransaction(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	recurring, ok := findOwnedRecurring(c, userID)
+	if !ok {
+		return
+	}
+
+	var req CreateRecurringTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "validation_error", Message: err.Error()})
+		return
+	}
+
+	if err := validateRecurringSchedule(req.Frequency, req.Interval, req.Count); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid_schedule", Message: err.Error()})
+		return
+	}
+
+	interval := req.Interval
+	if interval == 0 {
+		interval = 1
+	}
+	currency := req.Currency
+	if currency == "" {
+		currency = "IDR"
+	}
+
+	recurring.Description = req.Description
+	recurring.Amount = req.Amount
+	recurring.Currency = currency
+	recurring.CategoryID = req.CategoryID
+	recurring.Frequency = req.Frequency
+	recurring.Interval = interval
+	recurring.ByDay = req.ByDay
+	recurring.ByMonthday = req.ByMonthday
+	recurring.DTStart = req.DTStart
+	recurring.Until = req.Until
+	recurring.Count = req.Count
+	recurring.NextRunAt = req.DTStart
+	recurring.Active = true
+
+	if err := DB.Save(&recurring).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal mengupdate transaksi berulang"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Transaksi berulang berhasil diupdate",
+		"recurring": recurring,
+	})
+}
+
+// DeleteRecurringTransaction deletes a recurring transaction template. It
+// does not touch Transaction rows already materialized from it.
+func DeleteRecurringTransaction(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	recurring, ok := findOwnedRecurring(c, userID)
+	if !ok {
+		return
+	}
+
+	if err := DB.Delete(&recurring).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal menghapus transaksi berulang"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Transaksi berulang berhasil dihapus"})
+}
+
+// RunRecurringNow materializes recurring's current due occurrence
+// immediately, regardless of whether NextRunAt has actually passed, and
+// advances the schedule the same way a normal tick would.
+func RunRecurringNow(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	recurring, ok := findOwnedRecurring(c, userID)
+	if !ok {
+		return
+	}
+	if !recurring.Active {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "recurring_inactive", Message: "Transaksi berulang ini sudah tidak aktif"})
+		return
+	}
+
+	txn, err := processRecurringDue(recurring)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "database_error", Message: "Gagal menjalankan transaksi berulang: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Transaksi berulang berhasil dijalankan",
+		"transaction": txn,
+	})
+}
+
+// clampToMonthDay returns year-month-day at midnight, clamping day down to
+// the last day of that month when day overflows it (e.g. day=31 in
+// February), the way calendar apps treat a monthly recurrence anchored on a
+// short month.
+func clampToMonthDay(year int, month time.Month, day int) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	if day < 1 {
+		day = 1
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// nextWeeklyByDay returns the next date after from whose weekday is in
+// byDay (a comma-separated list of MO/TU/.../SU), stepping a day at a time.
+// It only supports Interval=1; a multi-week interval combined with BYDAY
+// falls back to the plain weekly advance in nextRecurringOccurrence.
+func nextWeeklyByDay(from time.Time, byDay string) time.Time {
+	days := make(map[time.Weekday]bool)
+	for _, abbrev := range splitCSV(byDay) {
+		if wd, ok := weekdayByAbbrev[abbrev]; ok {
+			days[wd] = true
+		}
+	}
+	if len(days) == 0 {
+		return from.AddDate(0, 0, 7)
+	}
+
+	candidate := from.AddDate(0, 0, 1)
+	for i := 0; i < 7; i++ {
+		if days[candidate.Weekday()] {
+			return candidate
+		}
+		candidate = candidate.AddDate(0, 0, 1)
+	}
+	return from.AddDate(0, 0, 7)
+}
+
+// splitCSV splits a comma-separated list, trimming surrounding whitespace
+// from each element, without pulling in strings.Fields/TrimSpace's wider
+// behavior than this simple case needs.
+func splitCSV(s string) []string {
+	var out []string
+	start := 0
+	for i := 0; i <= len(s); i++ {
+		if i == len(s) || s[i] == ',' {
+			if i > start {
+				out = append(out, s[start:i])
+			}
+			start = i + 1
+		}
+	}
+	return out
+}
+
+// nextRecurringOccurrence computes the next occurrence date after `from`
+// (the occurrence date just materialized) per rt's FREQ/INTERVAL/BYDAY/
+// BYMONTHDAY fields.
+func nextRecurringOccurrence(rt RecurringTransaction, from time.Time) time.Time {
+	interval := rt.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch rt.Frequency {
+	case "DAILY":
+		return from.AddDate(0, 0, interval)
+	case "WEEKLY":
+		if rt.ByDay != "" && interval == 1 {
+			return nextWeeklyByDay(from, rt.ByDay)
+		}
+		return from.AddDate(0, 0, 7*interval)
+	case "MONTHLY":
+		day := rt.ByMonthday
+		if day == 0 {
+			day = rt.DTStart.Day()
+		}
+		next := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, interval, 0)
+		return clampToMonthDay(next.Year(), next.Month(), day)
+	default:
+		return from.AddDate(0, 0, interval)
+	}
+}
+
+// materializeRecurringOccurrence creates the Transaction for rt's occurrence
+// on occurrenceDate inside tx, unless one already exists for that date (the
+// unique index on RecurringOccurrence makes this idempotent even if called
+// twice, e.g. a tick that runs again after a crash before advancing
+// NextRunAt). Returns the created or pre-existing Transaction.
+func materializeRecurringOccurrence(tx *gorm.DB, rt RecurringTransaction, occurrenceDate time.Time) (Transaction, error) {
+	var existing RecurringOccurrence
+	err := tx.Where("recurring_id = ? AND occurrence_date = ?", rt.ID, occurrenceDate).First(&existing).Error
+	if err == nil {
+		var txn Transaction
+		return txn, tx.First(&txn, existing.TransactionID).Error
+	}
+	if err != gorm.ErrRecordNotFound {
+		return Transaction{}, err
+	}
+
+	txn := Transaction{
+		Description:      rt.Description,
+		Amount:           rt.Amount,
+		TransactionDate:  occurrenceDate,
+		UserID:           rt.UserID,
+		CategoryID:       rt.CategoryID,
+		Currency:         rt.Currency,
+		PredictionMethod: "recurring",
+	}
+
+	knowledge, err := nextServerKnowledge(tx, rt.UserID)
+	if err != nil {
+		return Transaction{}, err
+	}
+	txn.ServerKnowledge = knowledge
+
+	if err := tx.Create(&txn).Error; err != nil {
+		return Transaction{}, err
+	}
+
+	// Materialized occurrences need the same balanced ledger legs a manually
+	// created Transaction gets, or they're invisible to
+	// GetTransactionSummary/GetMonthlyStats and /ledger/transactions.
+	if err := postTransactionEntries(tx, txn); err != nil {
+		return Transaction{}, err
+	}
+
+	occurrence := RecurringOccurrence{RecurringID: rt.ID, OccurrenceDate: occurrenceDate, TransactionID: txn.ID}
+	if err := tx.Create(&occurrence).Error; err != nil {
+		return Transaction{}, err
+	}
+	return txn, nil
+}
+
+// processRecurringDue materializes rt's occurrence at rt.NextRunAt and
+// advances its schedule, deactivating it once Until/Count is reached. It's
+// shared by the scheduler tick and the manual run-now endpoint.
+func processRecurringDue(rt RecurringTransaction) (Transaction, error) {
+	var txn Transaction
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		txn, err = materializeRecurringOccurrence(tx, rt, rt.NextRunAt)
+		if err != nil {
+			return err
+		}
+
+		rt.OccurrenceCount++
+		next := nextRecurringOccurrence(rt, rt.NextRunAt)
+
+		active := true
+		if rt.Count != nil && rt.OccurrenceCount >= *rt.Count {
+			active = false
+		}
+		if rt.Until != nil && next.After(*rt.Until) {
+			active = false
+		}
+
+		rt.NextRunAt = next
+		rt.Active = active
+		return tx.Save(&rt).Error
+	})
+	return txn, err
+}
+
+// recurringSchedulerState tracks the background scheduler's last tick for
+// GET /health, guarded by a mutex since the tick runs on its own goroutine.
+type recurringSchedulerState struct {
+	mu       sync.Mutex
+	lastTick time.Time
+	lastErr  error
+}
+
+var schedulerState recurringSchedulerState
+
+func (s *recurringSchedulerState) record(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastTick = time.Now()
+	s.lastErr = err
+}
+
+func (s *recurringSchedulerState) snapshot() (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastTick, s.lastErr
+}
+
+// recurringSchedulerHealth reports the scheduler's last-tick status for the
+// /health handler in app.go.
+func recurringSchedulerHealth() gin.H {
+	lastTick, err := schedulerState.snapshot()
+	health := gin.H{}
+	if lastTick.IsZero() {
+		health["status"] = "not_started"
+	} else {
+		health["status"] = "ok"
+		health["last_tick"] = lastTick
+	}
+	if err != nil {
+		health["status"] = "error"
+		health["last_error"] = err.Error()
+	}
+	return health
+}
+
+// runRecurringTick materializes every RecurringTransaction whose NextRunAt
+// has passed and records the result for recurringSchedulerHealth.
+func runRecurringTick() {
+	var due []RecurringTransaction
+	if err := DB.Where("active = ? AND next_run_at <= ?", true, time.Now()).Find(&due).Error; err != nil {
+		log.Printf("recurring scheduler: failed to load due transactions: %v", err)
+		schedulerState.record(err)
+		return
+	}
+
+	for _, rt := range due {
+		if _, err := processRecurringDue(rt); err != nil {
+			log.Printf("recurring scheduler: failed to materialize recurring transaction %d: %v", rt.ID, err)
+		}
+	}
+
+	schedulerState.record(nil)
+}
+
+// startRecurringScheduler ticks every interval, materializing due recurring
+// transactions, until ctx is cancelled. Intended to be called as a goroutine
+// from Serve with a 1-minute interval and a context cancelled on shutdown.
+func startRecurringScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			runRecurringTick()
+		}
+	}
+}
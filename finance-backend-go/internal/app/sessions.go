@@ -0,0 +1,287 @@
+package app
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// revokedSessionCacheKey namespaces the JWT-revocation blocklist so a logout
+// is picked up on the very next request without waiting on a DB round trip.
+func revokedSessionCacheKey(sessionID uint) string {
+	return fmt.Sprintf("revoked_session:%d", sessionID)
+}
+
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+// generateRefreshToken returns a random, URL-safe opaque token. Only its hash
+// is ever persisted, so a leaked database dump can't be replayed as-is.
+func generateRefreshToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// createSession issues a new refresh token and records it as a Session row,
+// scoped to the requesting user-agent/IP for the sessions list UI.
+func createSession(userID uint, c *gin.Context) (Session, string, error) {
+	rawToken, err := generateRefreshToken()
+	if err != nil {
+		return Session{}, "", err
+	}
+
+	session := Session{
+		UserID:           userID,
+		RefreshTokenHash: hashRefreshToken(rawToken),
+		UserAgent:        c.Request.UserAgent(),
+		IP:               c.ClientIP(),
+		ExpiresAt:        time.Now().Add(refreshTokenTTL),
+	}
+	if err := DB.Create(&session).Error; err != nil {
+		return Session{}, "", err
+	}
+
+	return session, rawToken, nil
+}
+
+// revokeSessionChain revokes every still-active session belonging to the
+// user, except exceptSessionID (pass 0 to revoke all of them). Called when a
+// refresh token is reused after rotation, since that indicates the token was
+// stolen and every descendant session is suspect.
+func revokeSessionChain(userID uint, exceptSessionID uint) {
+	now := time.Now()
+
+	scope := func(tx *gorm.DB) *gorm.DB {
+		tx = tx.Where("user_id = ? AND revoked_at IS NULL", userID)
+		if exceptSessionID != 0 {
+			tx = tx.Where("id != ?", exceptSessionID)
+		}
+		return tx
+	}
+
+	var activeSessions []Session
+	scope(DB).Find(&activeSessions)
+
+	scope(DB.Model(&Session{})).Update("revoked_at", now)
+
+	if AppCache != nil {
+		for _, session := range activeSessions {
+			AppCache.Set(revokedSessionCacheKey(session.ID), "1", accessTokenTTL)
+		}
+	}
+}
+
+// RefreshToken verifies the presented refresh token, rotates it (revoking the
+// old session and linking it to the new one via replaced_by), and issues a
+// fresh access + refresh token pair. Presenting a refresh token that has
+// already been rotated away is treated as token theft and revokes every
+// active session for that user.
+func RefreshToken(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	tokenHash := hashRefreshToken(req.RefreshToken)
+
+	var session Session
+	if err := DB.Where("refresh_token_hash = ?", tokenHash).First(&session).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "invalid_refresh_token",
+			Message: "Refresh token tidak valid",
+		})
+		return
+	}
+
+	if session.RevokedAt != nil {
+		// A revoked-but-already-replaced token being presented again means it
+		// was copied by someone else after the legitimate client rotated it.
+		revokeSessionChain(session.UserID, 0)
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "refresh_token_reused",
+			Message: "Refresh token sudah dipakai sebelumnya, semua session telah dicabut",
+		})
+		return
+	}
+
+	if session.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "refresh_token_expired",
+			Message: "Refresh token sudah expired, silakan login kembali",
+		})
+		return
+	}
+
+	var user User
+	if err := DB.First(&user, session.UserID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "invalid_refresh_token",
+			Message: "Refresh token tidak valid",
+		})
+		return
+	}
+
+	newSession, rawRefreshToken, err := createSession(user.ID, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "session_error",
+			Message: "Gagal membuat session baru",
+		})
+		return
+	}
+
+	now := time.Now()
+	session.RevokedAt = &now
+	session.ReplacedBy = &newSession.ID
+	if err := DB.Save(&session).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal merotasi session",
+		})
+		return
+	}
+
+	accessToken, err := generateJWT(user.ID, user.Username, user.Role, newSession.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "token_error",
+			Message: "Gagal membuat token",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, AuthResponse{
+		Token:        accessToken,
+		RefreshToken: rawRefreshToken,
+		UserID:       user.ID,
+		Username:     user.Username,
+		Message:      "Token berhasil diperbarui",
+	})
+}
+
+// LogoutUser revokes the session tied to the caller's current access token.
+func LogoutUser(c *gin.Context) {
+	sessionID, exists := c.Get("session_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "Session tidak ditemukan",
+		})
+		return
+	}
+
+	now := time.Now()
+	if err := DB.Model(&Session{}).Where("id = ?", sessionID).Update("revoked_at", now).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal logout",
+		})
+		return
+	}
+
+	if AppCache != nil {
+		AppCache.Set(revokedSessionCacheKey(sessionID.(uint)), "1", accessTokenTTL)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Logout berhasil",
+	})
+}
+
+// ListSessions lists the caller's sessions, most recent first, so they can
+// spot and revoke ones they don't recognize.
+func ListSessions(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	currentSessionID, _ := c.Get("session_id")
+
+	var sessions []Session
+	if err := DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengambil data session",
+		})
+		return
+	}
+
+	type sessionResponse struct {
+		ID        uint      `json:"id"`
+		UserAgent string    `json:"user_agent"`
+		IP        string    `json:"ip"`
+		CreatedAt time.Time `json:"created_at"`
+		ExpiresAt time.Time `json:"expires_at"`
+		Current   bool      `json:"current"`
+	}
+
+	responses := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, sessionResponse{
+			ID:        session.ID,
+			UserAgent: session.UserAgent,
+			IP:        session.IP,
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+			Current:   currentSessionID == session.ID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": responses,
+	})
+}
+
+// RevokeSession revokes one of the caller's own sessions by ID.
+func RevokeSession(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	sessionIDParam := c.Param("id")
+
+	result := DB.Model(&Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionIDParam, userID).
+		Update("revoked_at", time.Now())
+
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mencabut session",
+		})
+		return
+	}
+
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "session_not_found",
+			Message: "Session tidak ditemukan",
+		})
+		return
+	}
+
+	if AppCache != nil {
+		if id, err := strconv.ParseUint(sessionIDParam, 10, 64); err == nil {
+			AppCache.Set(revokedSessionCacheKey(uint(id)), "1", accessTokenTTL)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session berhasil dicabut",
+	})
+}
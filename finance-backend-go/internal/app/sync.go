@@ -0,0 +1,114 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// nextServerKnowledge atomically bumps userID's sync counter and returns the
+// new value, so the caller can stamp it onto the row it's about to
+// create/update or the tombstone it's about to write. Pass a transaction
+// (tx) when the bump must commit atomically with the row change.
+func nextServerKnowledge(tx *gorm.DB, userID uint) (uint64, error) {
+	if err := tx.Model(&User{}).Where("id = ?", userID).
+		UpdateColumn("server_knowledge", gorm.Expr("server_knowledge + 1")).Error; err != nil {
+		return 0, err
+	}
+
+	var user User
+	if err := tx.Select("server_knowledge").First(&user, userID).Error; err != nil {
+		return 0, err
+	}
+	return user.ServerKnowledge, nil
+}
+
+// recordTombstone stamps a SyncTombstone at knowledge for a hard-deleted
+// entity, so GetSync can tell a client to drop it even though the row itself
+// no longer exists to carry a ServerKnowledge value.
+func recordTombstone(tx *gorm.DB, userID uint, entityType SyncEntityType, entityID uint, knowledge uint64) error {
+	return tx.Create(&SyncTombstone{
+		UserID:          userID,
+		EntityType:      entityType,
+		EntityID:        entityID,
+		ServerKnowledge: knowledge,
+	}).Error
+}
+
+// SyncResponse is the body for GET /api/v1/sync: everything the caller's
+// since_knowledge cursor hasn't seen yet, plus the cursor to send next time.
+type SyncResponse struct {
+	Transactions    []Transaction   `json:"transactions"`
+	Categories      []Category      `json:"categories"`
+	Deleted         []SyncTombstone `json:"deleted"`
+	ServerKnowledge uint64          `json:"server_knowledge"`
+}
+
+// GetSync implements a last_knowledge_of_server style delta sync, the same
+// cursor pattern fetchYNABTransactions consumes from YNAB's API (see
+// ynab.go), but served by this app for its own mobile/offline clients.
+// GET /api/v1/sync?since_knowledge=N returns only transactions and
+// categories created or updated since N, plus tombstones for anything
+// deleted since N, so a client can reconcile incrementally instead of
+// re-fetching everything.
+func GetSync(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	uid := userID.(uint)
+
+	since, err := strconv.ParseUint(c.DefaultQuery("since_knowledge", "0"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: "since_knowledge harus berupa angka",
+		})
+		return
+	}
+
+	var transactions []Transaction
+	if err := DB.Where("user_id = ? AND server_knowledge > ?", uid, since).
+		Order("server_knowledge ASC").Find(&transactions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengambil transaksi",
+		})
+		return
+	}
+
+	var categories []Category
+	if err := DB.Where("user_id = ? AND server_knowledge > ?", uid, since).
+		Order("server_knowledge ASC").Find(&categories).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengambil kategori",
+		})
+		return
+	}
+
+	var deleted []SyncTombstone
+	if err := DB.Where("user_id = ? AND server_knowledge > ?", uid, since).
+		Order("server_knowledge ASC").Find(&deleted).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengambil riwayat penghapusan",
+		})
+		return
+	}
+
+	var user User
+	if err := DB.Select("server_knowledge").First(&user, uid).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal mengambil status sinkronisasi",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SyncResponse{
+		Transactions:    transactions,
+		Categories:      categories,
+		Deleted:         deleted,
+		ServerKnowledge: user.ServerKnowledge,
+	})
+}
@@ -1,9 +1,11 @@
-package main
+package app
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,12 +18,14 @@ type CreateTransactionRequest struct {
 	Description     string     `json:"description" binding:"required,max=255"`
 	Amount          float64    `json:"amount" binding:"required"`
 	TransactionDate *time.Time `json:"transaction_date"`
+	Currency        string     `json:"currency" binding:"omitempty,len=3"`
 }
 
 type TransactionResponse struct {
 	ID               uint      `json:"id"`
 	Description      string    `json:"description"`
 	Amount           float64   `json:"amount"`
+	Currency         string    `json:"currency"`
 	TransactionDate  time.Time `json:"transaction_date"`
 	CategoryID       *uint     `json:"category_id"`
 	CategoryName     string    `json:"category_name,omitempty"`
@@ -30,6 +34,11 @@ type TransactionResponse struct {
 	UpdatedAt        time.Time `json:"updated_at"`
 	AIConfidence     float64   `json:"ai_confidence,omitempty"`
 	PredictionMethod string    `json:"prediction_method,omitempty"`
+	// BaseCurrency/ConvertedAmount/ExchangeRate let a client show "original
+	// amount + converted amount" without a second FX lookup; see fx.go.
+	BaseCurrency    string  `json:"base_currency,omitempty"`
+	ConvertedAmount float64 `json:"converted_amount,omitempty"`
+	ExchangeRate    float64 `json:"exchange_rate,omitempty"`
 }
 
 // JWT Middleware
@@ -70,9 +79,38 @@ func JWTMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Fast path: check the revocation blocklist before hitting the DB, so a
+		// client retrying with a just-logged-out token doesn't cost a query.
+		if AppCache != nil {
+			if _, revoked := AppCache.Get(revokedSessionCacheKey(claims.SessionID)); revoked {
+				c.JSON(http.StatusUnauthorized, ErrorResponse{
+					Error:   "session_revoked",
+					Message: "Session sudah tidak berlaku, silakan login kembali",
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		// Reject tokens whose session has since been revoked (logout, reuse
+		// detection, or explicit DELETE /auth/sessions/:id) even if the JWT
+		// itself hasn't expired yet.
+		var session Session
+		if err := DB.First(&session, claims.SessionID).Error; err != nil || session.UserID != claims.UserID || session.RevokedAt != nil {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "session_revoked",
+				Message: "Session sudah tidak berlaku, silakan login kembali",
+			})
+			c.Abort()
+			return
+		}
+
 		// Set user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("session_id", claims.SessionID)
+		c.Set("reauth_at", claims.ReauthAt)
+		c.Set("role", claims.Role)
 
 		c.Next()
 	}
@@ -102,7 +140,9 @@ func CreateTransaction(c *gin.Context) {
 	}
 
 	// Call AI service to get category prediction
-	predictedCategory, confidence, err := GetCategoryWithConfidence(req.Description)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), aiContextTimeout)
+	defer cancel()
+	predictedCategory, confidence, err := GetCategoryWithConfidence(ctx, userID.(uint), req.Description)
 	if err != nil {
 		// Log error but don't fail the transaction
 		log.Printf("AI categorization failed: %v", err)
@@ -126,16 +166,38 @@ func CreateTransaction(c *gin.Context) {
 		transactionDate = *req.TransactionDate
 	}
 
+	currency := req.Currency
+	if currency == "" {
+		currency = "IDR"
+	}
+
 	// Create new transaction
 	transaction := Transaction{
-		Description:     req.Description,
-		Amount:          req.Amount,
-		TransactionDate: transactionDate,
-		UserID:          userID.(uint),
-		CategoryID:      &categoryID,
+		Description:      req.Description,
+		Amount:           req.Amount,
+		Currency:         currency,
+		TransactionDate:  transactionDate,
+		UserID:           userID.(uint),
+		CategoryID:       &categoryID,
+		PredictionMethod: predictionMethod,
+		AIConfidence:     confidence,
 	}
 
-	if err := DB.Create(&transaction).Error; err != nil {
+	// Posting the ledger legs alongside the header keeps them atomic: a
+	// transaction never exists without the balanced Assets:Default /
+	// Income-or-Expenses postings GetTransactionSummary/GetMonthlyStats read.
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		knowledge, err := nextServerKnowledge(tx, userID.(uint))
+		if err != nil {
+			return err
+		}
+		transaction.ServerKnowledge = knowledge
+
+		if err := tx.Create(&transaction).Error; err != nil {
+			return err
+		}
+		return postTransactionEntries(tx, transaction)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "database_error",
 			Message: "Gagal menyimpan transaksi",
@@ -143,11 +205,17 @@ func CreateTransaction(c *gin.Context) {
 		return
 	}
 
+	recordPrediction(predictionMethod, categoryName, confidence)
+
+	baseCurrency := userBaseCurrency(userID.(uint))
+	convertedAmount, rate := convertToBaseCurrency(transaction.Amount, transaction.Currency, baseCurrency, transaction.TransactionDate)
+
 	// Return response with AI categorization info
 	response := TransactionResponse{
 		ID:               transaction.ID,
 		Description:      transaction.Description,
 		Amount:           transaction.Amount,
+		Currency:         transaction.Currency,
 		TransactionDate:  transaction.TransactionDate,
 		CategoryID:       transaction.CategoryID,
 		CategoryName:     categoryName,
@@ -156,6 +224,9 @@ func CreateTransaction(c *gin.Context) {
 		UpdatedAt:        transaction.UpdatedAt,
 		AIConfidence:     confidence,
 		PredictionMethod: predictionMethod,
+		BaseCurrency:     baseCurrency,
+		ConvertedAmount:  convertedAmount,
+		ExchangeRate:     rate,
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
@@ -169,15 +240,35 @@ func CreateTransaction(c *gin.Context) {
 	})
 }
 
+// categoryLookupCacheTTL controls how long a (user, category name) -> ID
+// mapping is cached, letting repeat categorizations skip the DB SELECT.
+const categoryLookupCacheTTL = 24 * time.Hour
+
+func categoryLookupCacheKey(userID uint, categoryName string) string {
+	return fmt.Sprintf("category_id:%d:%s", userID, categoryName)
+}
+
 // findOrCreateCategory finds existing category or creates new one
 func findOrCreateCategory(userID uint, categoryName string) (uint, string, string, error) {
 	var category Category
 	var predictionMethod string = "ai_categorization"
 
+	cacheKey := categoryLookupCacheKey(userID, categoryName)
+	if AppCache != nil {
+		if cached, ok := AppCache.Get(cacheKey); ok {
+			if categoryID, err := strconv.ParseUint(cached, 10, 64); err == nil {
+				return uint(categoryID), categoryName, predictionMethod, nil
+			}
+		}
+	}
+
 	// First, try to find existing category (user's personal or system category)
 	err := DB.Where("name = ? AND (user_id = ? OR user_id IS NULL)", categoryName, userID).First(&category).Error
 	if err == nil {
 		// Category exists
+		if AppCache != nil {
+			AppCache.Set(cacheKey, strconv.FormatUint(uint64(category.ID), 10), categoryLookupCacheTTL)
+		}
 		return category.ID, category.Name, predictionMethod, nil
 	}
 
@@ -185,13 +276,17 @@ func findOrCreateCategory(userID uint, categoryName string) (uint, string, strin
 	if err == gorm.ErrRecordNotFound {
 		newCategory := Category{
 			Name:   categoryName,
-			UserID: userID,
+			UserID: &userID,
 		}
 
 		if err := DB.Create(&newCategory).Error; err != nil {
 			return 0, "", predictionMethod, fmt.Errorf("failed to create category: %w", err)
 		}
 
+		if AppCache != nil {
+			AppCache.Set(cacheKey, strconv.FormatUint(uint64(newCategory.ID), 10), categoryLookupCacheTTL)
+		}
+
 		return newCategory.ID, newCategory.Name, predictionMethod + "_new_category", nil
 	}
 
@@ -249,6 +344,7 @@ func GetTransactions(c *gin.Context) {
 	}
 
 	// Convert to response format
+	baseCurrency := userBaseCurrency(userID.(uint))
 	var responseTransactions []TransactionResponse
 	for _, transaction := range transactions {
 		var categoryName string
@@ -256,18 +352,24 @@ func GetTransactions(c *gin.Context) {
 			categoryName = transaction.Category.Name
 		}
 
+		convertedAmount, rate := convertToBaseCurrency(transaction.Amount, transaction.Currency, baseCurrency, transaction.TransactionDate)
+
 		responseTransactions = append(responseTransactions, TransactionResponse{
 			ID:               transaction.ID,
 			Description:      transaction.Description,
 			Amount:           transaction.Amount,
+			Currency:         transaction.Currency,
 			TransactionDate:  transaction.TransactionDate,
 			CategoryID:       transaction.CategoryID,
 			CategoryName:     categoryName,
 			UserID:           transaction.UserID,
 			CreatedAt:        transaction.CreatedAt,
 			UpdatedAt:        transaction.UpdatedAt,
-			AIConfidence:     0.0,      // Historical transactions don't have AI confidence
-			PredictionMethod: "manual", // Historical transactions are manual
+			AIConfidence:     transaction.AIConfidence,
+			PredictionMethod: transaction.PredictionMethod,
+			BaseCurrency:     baseCurrency,
+			ConvertedAmount:  convertedAmount,
+			ExchangeRate:     rate,
 		})
 	}
 
@@ -0,0 +1,390 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Minimal client for the subset of the YNAB API (https://api.youneedabudget.com/v1)
+// this app needs: listing budgets and pulling transactions with a delta cursor.
+// Hand-rolled in the same style as ai_client.go rather than a full generated
+// client, since we only ever touch two endpoints.
+
+const ynabAPIBaseURL = "https://api.youneedabudget.com/v1"
+
+var ynabHTTPClient = &http.Client{
+	Timeout: 15 * time.Second,
+}
+
+type ynabBudgetSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ynabTransaction struct {
+	ID           string `json:"id"`
+	Date         string `json:"date"`
+	Amount       int64  `json:"amount"` // milliunits, per YNAB convention
+	PayeeName    string `json:"payee_name"`
+	CategoryName string `json:"category_name"`
+	Cleared      string `json:"cleared"`
+	Deleted      bool   `json:"deleted"`
+}
+
+type ynabTransactionsResponse struct {
+	Data struct {
+		Transactions    []ynabTransaction `json:"transactions"`
+		ServerKnowledge int64             `json:"server_knowledge"`
+	} `json:"data"`
+}
+
+type ynabBudgetsResponse struct {
+	Data struct {
+		Budgets []ynabBudgetSummary `json:"budgets"`
+	} `json:"data"`
+}
+
+func ynabRequest(accessToken, method, path string) ([]byte, error) {
+	req, err := http.NewRequest(method, ynabAPIBaseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create YNAB request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ynabHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach YNAB: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read YNAB response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("YNAB API error (%d): %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+// fetchYNABTransactions pulls transactions for budgetID, only those created or
+// updated since lastKnowledge (0 means "all"), per YNAB's delta request pattern.
+func fetchYNABTransactions(accessToken, budgetID string, lastKnowledge int64) ([]ynabTransaction, int64, error) {
+	path := fmt.Sprintf("/budgets/%s/transactions?last_knowledge_of_server=%d", budgetID, lastKnowledge)
+	body, err := ynabRequest(accessToken, "GET", path)
+	if err != nil {
+		return nil, lastKnowledge, err
+	}
+
+	var parsed ynabTransactionsResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, lastKnowledge, fmt.Errorf("failed to parse YNAB transactions: %w", err)
+	}
+
+	return parsed.Data.Transactions, parsed.Data.ServerKnowledge, nil
+}
+
+// ConnectYNAB links a YNAB personal access token + budget to the current user.
+func ConnectYNAB(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+
+	var req struct {
+		AccessToken string `json:"access_token" binding:"required"`
+		BudgetID    string `json:"budget_id" binding:"required"`
+		AutoSync    bool   `json:"auto_sync"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "validation_error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Verify the token/budget pair actually works before persisting it.
+	body, err := ynabRequest(req.AccessToken, "GET", "/budgets")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "ynab_auth_failed",
+			Message: "Gagal memverifikasi token YNAB: " + err.Error(),
+		})
+		return
+	}
+	var budgets ynabBudgetsResponse
+	if err := json.Unmarshal(body, &budgets); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "ynab_response_error",
+			Message: "Gagal membaca respons YNAB",
+		})
+		return
+	}
+	found := false
+	for _, b := range budgets.Data.Budgets {
+		if b.ID == req.BudgetID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "budget_not_found",
+			Message: "Budget ID tidak ditemukan pada akun YNAB tersebut",
+		})
+		return
+	}
+
+	account := YNABAccount{
+		UserID:      userID.(uint),
+		AccessToken: req.AccessToken,
+		BudgetID:    req.BudgetID,
+		AutoSync:    req.AutoSync,
+	}
+
+	// One YNAB link per user: replace any existing connection.
+	if err := DB.Where("user_id = ?", userID).Delete(&YNABAccount{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal menghapus koneksi YNAB lama",
+		})
+		return
+	}
+	if err := DB.Create(&account).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal menyimpan koneksi YNAB",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Koneksi YNAB berhasil dibuat",
+	})
+}
+
+// SyncYNAB pulls the delta of transactions since the stored cursor and feeds
+// them into the existing Transaction/Category tables.
+func SyncYNAB(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+
+	var account YNABAccount
+	if err := DB.Where("user_id = ?", userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "ynab_not_connected",
+			Message: "Akun belum terhubung dengan YNAB",
+		})
+		return
+	}
+
+	imported, err := syncYNABAccount(&account)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, ErrorResponse{
+			Error:   "ynab_sync_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":                  "Sinkronisasi YNAB selesai",
+		"imported_count":           imported,
+		"last_knowledge_of_server": account.LastKnowledgeOfServer,
+	})
+}
+
+// syncYNABAccount performs one delta sync for account and advances its cursor.
+// Shared by the manual SyncYNAB handler and the auto-sync scheduler.
+func syncYNABAccount(account *YNABAccount) (int, error) {
+	transactions, newKnowledge, err := fetchYNABTransactions(account.AccessToken, account.BudgetID, account.LastKnowledgeOfServer)
+	if err != nil {
+		return 0, err
+	}
+
+	imported := 0
+	for _, ynabTxn := range transactions {
+		if ynabTxn.Deleted {
+			continue
+		}
+
+		amount := float64(ynabTxn.Amount) / 1000.0 // YNAB amounts are in milliunits
+		txnDate, err := time.Parse("2006-01-02", ynabTxn.Date)
+		if err != nil {
+			txnDate = time.Now()
+		}
+
+		description := ynabTxn.PayeeName
+		if description == "" {
+			description = "YNAB Transaction"
+		}
+
+		var categoryID *uint
+
+		if ynabTxn.CategoryName != "" {
+			// YNAB already categorized this one; reuse its category name.
+			id, _, _, err := findOrCreateCategory(account.UserID, ynabTxn.CategoryName)
+			if err == nil {
+				categoryID = &id
+			}
+		} else {
+			// Unknown payee, no YNAB category: fall back to our AI categorizer.
+			ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+			predicted, _, err := GetCategoryWithConfidence(ctx, account.UserID, description)
+			cancel()
+			if err != nil {
+				log.Printf("YNAB sync: AI categorization failed for '%s': %v", description, err)
+				predicted = "Lainnya"
+			}
+			id, _, _, err := findOrCreateCategory(account.UserID, predicted)
+			if err == nil {
+				categoryID = &id
+			}
+		}
+
+		var existing Transaction
+		txn := Transaction{
+			Description:     description,
+			Amount:          amount,
+			TransactionDate: txnDate,
+			UserID:          account.UserID,
+			CategoryID:      categoryID,
+		}
+
+		// Reconciled transactions from YNAB overwrite the same way
+		// RecategorizeTransaction's manual path does, keyed on date+description
+		// since YNAB doesn't map cleanly onto our transaction IDs.
+		if err := DB.Where("description = ? AND transaction_date = ? AND user_id = ?", description, txnDate, account.UserID).First(&existing).Error; err == nil {
+			if err := DB.Transaction(func(tx *gorm.DB) error {
+				knowledge, err := nextServerKnowledge(tx, account.UserID)
+				if err != nil {
+					return err
+				}
+				existing.CategoryID = categoryID
+				existing.PredictionMethod = "ynab_sync"
+				existing.ServerKnowledge = knowledge
+				if err := tx.Save(&existing).Error; err != nil {
+					return err
+				}
+
+				// The category (and therefore the offsetting Income/Expenses
+				// leg) may have just changed; drop and relay the postings the
+				// same way UpdateTransaction does, skipping entries that
+				// aren't the plain 2-leg shape postTransactionEntries produces.
+				simple, err := hasSimpleLedgerPostings(tx, existing.ID)
+				if err != nil {
+					return err
+				}
+				if !simple {
+					return nil
+				}
+				if err := tx.Where("transaction_id = ?", existing.ID).Delete(&Posting{}).Error; err != nil {
+					return err
+				}
+				return postTransactionEntries(tx, existing)
+			}); err != nil {
+				continue
+			}
+		} else {
+			if err := DB.Transaction(func(tx *gorm.DB) error {
+				knowledge, err := nextServerKnowledge(tx, account.UserID)
+				if err != nil {
+					return err
+				}
+				txn.ServerKnowledge = knowledge
+				if err := tx.Create(&txn).Error; err != nil {
+					return err
+				}
+				return postTransactionEntries(tx, txn)
+			}); err != nil {
+				continue
+			}
+		}
+		imported++
+	}
+
+	account.LastKnowledgeOfServer = newKnowledge
+	now := time.Now()
+	account.LastSyncedAt = &now
+	if err := DB.Save(account).Error; err != nil {
+		return imported, fmt.Errorf("failed to persist sync cursor: %w", err)
+	}
+
+	return imported, nil
+}
+
+// DisconnectYNAB removes the current user's YNAB connection.
+func DisconnectYNAB(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User ID tidak ditemukan",
+		})
+		return
+	}
+
+	result := DB.Where("user_id = ?", userID).Delete(&YNABAccount{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Gagal memutuskan koneksi YNAB",
+		})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "ynab_not_connected",
+			Message: "Akun belum terhubung dengan YNAB",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Koneksi YNAB berhasil diputuskan",
+	})
+}
+
+// startYNABAutoSyncScheduler periodically refreshes accounts that opted into
+// auto-sync. Runs for the lifetime of the process; call from main() as a goroutine.
+func startYNABAutoSyncScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		var accounts []YNABAccount
+		if err := DB.Where("auto_sync = ?", true).Find(&accounts).Error; err != nil {
+			log.Printf("YNAB auto-sync: failed to load accounts: %v", err)
+			continue
+		}
+
+		for i := range accounts {
+			account := accounts[i]
+			if _, err := syncYNABAccount(&account); err != nil {
+				log.Printf("YNAB auto-sync: sync failed for user %d: %v", account.UserID, err)
+			}
+		}
+	}
+}